@@ -0,0 +1,463 @@
+package geometry
+
+// One-dimensional postgres arrays of the geometric types.  Elements are
+// formatted and parsed by each type's own Value and Scan methods.  Note that
+// postgres delimits the elements of a box[] with ';' rather than ',', since a
+// box's own text contains commas.
+// info from http://www.postgresql.org/docs/9.3/static/arrays.html#ARRAYS-IO
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// assert that types implement driver.Valuer
+var _ driver.Valuer = PointArray{}
+var _ driver.Valuer = SegmentArray{}
+var _ driver.Valuer = BoxArray{}
+var _ driver.Valuer = CircleArray{}
+var _ driver.Valuer = PathArray{}
+var _ driver.Valuer = PolygonArray{}
+var _ driver.Valuer = LineArray{}
+
+// Checks that the driver sent the text form of an array, and splits it into
+// its elements.  A NULL array is returned as a nil slice.
+func scanArray(src interface{}, delim byte) ([]string, error) {
+	switch s := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return parseArray(string(s), delim)
+	case string:
+		return parseArray(s, delim)
+	}
+
+	return nil, &SourceError{Type: "array", Want: "[]byte or string", Got: src}
+}
+
+// parseArray splits a one-dimensional postgres array literal into the text
+// of its elements, with any quoting and escaping removed.  NULL elements can
+// not be represented by the array types, and are an error.
+func parseArray(s string, delim byte) ([]string, error) {
+	fail := &SyntaxError{Type: "array", Input: s}
+	in := s
+
+	s = strings.TrimLeft(s, space)
+
+	// skip any explicit dimensions, like [1:3]=
+	if strings.HasPrefix(s, "[") {
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			return nil, fail
+		}
+		s = strings.TrimLeft(s[i+1:], space)
+	}
+
+	if !strings.HasPrefix(s, "{") {
+		return nil, fail
+	}
+	s = strings.TrimLeft(s[1:], space)
+
+	elems := []string{}
+
+	if strings.HasPrefix(s, "}") {
+		if strings.TrimLeft(s[1:], space) != "" {
+			return nil, fail
+		}
+		return elems, nil
+	}
+
+	for {
+		var elem []byte
+		quoted := false
+
+		s = strings.TrimLeft(s, space)
+		if s == "" {
+			return nil, fail
+		}
+
+		switch s[0] {
+		case '{':
+			return nil, &SyntaxError{Type: "array", Input: in, Reason: "multi-dimensional arrays are not supported"}
+		case '"':
+			quoted = true
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+					if i == len(s) {
+						return nil, fail
+					}
+				}
+				elem = append(elem, s[i])
+			}
+			if i == len(s) {
+				return nil, fail
+			}
+			s = s[i+1:]
+		default:
+			i := 0
+			for ; i < len(s) && s[i] != delim && s[i] != '}'; i++ {
+				if s[i] == '\\' {
+					i++
+					if i == len(s) {
+						return nil, fail
+					}
+				}
+				elem = append(elem, s[i])
+			}
+			elem = []byte(strings.TrimRight(string(elem), space))
+			if len(elem) == 0 {
+				return nil, fail
+			}
+			s = s[i:]
+		}
+
+		if !quoted && strings.EqualFold(string(elem), "NULL") {
+			return nil, &SyntaxError{Type: "array", Input: in, Reason: "NULL elements are not supported"}
+		}
+
+		elems = append(elems, string(elem))
+
+		s = strings.TrimLeft(s, space)
+		switch {
+		case strings.HasPrefix(s, string(delim)):
+			s = s[1:]
+		case strings.HasPrefix(s, "}"):
+			if strings.TrimLeft(s[1:], space) != "" {
+				return nil, fail
+			}
+			return elems, nil
+		default:
+			return nil, fail
+		}
+	}
+}
+
+// appendArray formats elements as a postgres array literal, quoting those
+// which need it.
+func appendArray(elems []driver.Valuer, delim byte) (driver.Value, error) {
+	b := make([]byte, 0, 10)
+	b = append(b, '{')
+
+	for i, e := range elems {
+		if i > 0 {
+			b = append(b, delim)
+		}
+
+		v, err := e.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		text, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("Expected []byte from array element, got %T instead", v)
+		}
+
+		if len(text) == 0 || strings.EqualFold(string(text), "NULL") || strings.ContainsAny(string(text), string(delim)+"{}\"\\"+space) {
+			b = append(b, '"')
+			for _, c := range text {
+				if c == '"' || c == '\\' {
+					b = append(b, '\\')
+				}
+				b = append(b, c)
+			}
+			b = append(b, '"')
+		} else {
+			b = append(b, text...)
+		}
+	}
+
+	b = append(b, '}')
+
+	return b, nil
+}
+
+// ----------
+
+// PointArray represents a postgres point[] array.  A nil PointArray is NULL.
+type PointArray []Point
+
+func (a *PointArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ',')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for PointArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(PointArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for PointArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a PointArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ',')
+}
+
+// ----------
+
+// SegmentArray represents a postgres lseg[] array.  A nil SegmentArray is NULL.
+type SegmentArray []Segment
+
+func (a *SegmentArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ',')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for SegmentArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(SegmentArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for SegmentArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a SegmentArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ',')
+}
+
+// ----------
+
+// BoxArray represents a postgres box[] array.  A nil BoxArray is NULL.
+type BoxArray []Box
+
+func (a *BoxArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ';')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for BoxArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(BoxArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for BoxArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a BoxArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ';')
+}
+
+// ----------
+
+// CircleArray represents a postgres circle[] array.  A nil CircleArray is NULL.
+type CircleArray []Circle
+
+func (a *CircleArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ',')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for CircleArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(CircleArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for CircleArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a CircleArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ',')
+}
+
+// ----------
+
+// PathArray represents a postgres path[] array.  A nil PathArray is NULL.
+type PathArray []Path
+
+func (a *PathArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ',')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for PathArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(PathArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for PathArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a PathArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ',')
+}
+
+// ----------
+
+// PolygonArray represents a postgres polygon[] array.  A nil PolygonArray is NULL.
+type PolygonArray []Polygon
+
+func (a *PolygonArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ',')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for PolygonArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(PolygonArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for PolygonArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a PolygonArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ',')
+}
+
+// ----------
+
+// LineArray represents a postgres line[] array.  A nil LineArray is NULL.
+type LineArray []Line
+
+func (a *LineArray) Scan(src interface{}) error {
+	elems, err := scanArray(src, ',')
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for LineArray: %w", err)
+	}
+
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+
+	r := make(LineArray, len(elems))
+	for i, e := range elems {
+		if err := r[i].Scan(e); err != nil {
+			return fmt.Errorf("Error while parsing data for LineArray: %w", err)
+		}
+	}
+
+	*a = r
+	return nil
+}
+
+func (a LineArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	elems := make([]driver.Valuer, len(a))
+	for i := range a {
+		elems[i] = a[i]
+	}
+
+	return appendArray(elems, ',')
+}
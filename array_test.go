@@ -0,0 +1,125 @@
+package geometry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestParseArray(t *testing.T) {
+
+	Convey("Given postgres array literals", t, func() {
+
+		Convey("Quoted and unquoted elements should be split", func() {
+			e, err := parseArray(`{"(1,2)","(3,4)"}`, ',')
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, []string{"(1,2)", "(3,4)"})
+
+			e, err = parseArray(`{(3,4),(1,2);(5,6),(0,0)}`, ';')
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, []string{"(3,4),(1,2)", "(5,6),(0,0)"})
+
+			e, err = parseArray(` { "a\"b" , c\,d , e } `, ',')
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, []string{`a"b`, "c,d", "e"})
+		})
+
+		Convey("Empty arrays and explicit dimensions should be accepted", func() {
+			e, err := parseArray("{}", ',')
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, []string{})
+
+			e, err = parseArray(`[0:1]={"(1,2)","(3,4)"}`, ',')
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, []string{"(1,2)", "(3,4)"})
+		})
+
+		Convey("Malformed, nested or NULL-containing arrays should be rejected", func() {
+			for _, s := range []string{"", "(1,2)", `{"(1,2)"`, `{"(1,2)}`, `{"(1,2)",}`, `{{"(1,2)"}}`, `{"(1,2)",NULL}`, `{"(1,2)"}x`} {
+				_, err := parseArray(s, ',')
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("A quoted NULL is not NULL", func() {
+			e, err := parseArray(`{"NULL"}`, ',')
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, []string{"NULL"})
+		})
+	})
+}
+
+func TestArrays(t *testing.T) {
+
+	Convey("Given arrays of geometric types", t, func() {
+
+		points := PointArray{NewPoint(1, 2), NewPoint(-3.5, 4e10)}
+		boxes := BoxArray{NewBox(Point{1, 2}, Point{3, 4}), NewBox(Point{0, 0}, Point{5, 6})}
+		lines := LineArray{NewLineCoefficients(1, -1, 0)}
+
+		Convey("Values should be postgres array literals", func() {
+			v, err := points.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, `{"(1,2)","(-3.5,4e+10)"}`)
+
+			v, err = boxes.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, `{((3,4),(1,2));((5,6),(0,0))}`)
+
+			v, err = lines.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, `{"{1,-1,0}"}`)
+
+			v, err = CircleArray{}.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, `{}`)
+		})
+
+		Convey("A nil array should be NULL, and NULL should scan as nil", func() {
+			v, err := PointArray(nil).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldBeNil)
+
+			r := PointArray{NewPoint(1, 2)}
+			So(r.Scan(nil), ShouldBeNil)
+			So(r, ShouldBeNil)
+		})
+
+		Convey("Arrays should scan from what postgres sends", func() {
+			var p PointArray
+			So(p.Scan([]byte(`{"(1,2)","(-3.5,4e+10)"}`)), ShouldBeNil)
+			So(p, ShouldResemble, points)
+
+			var b BoxArray
+			So(b.Scan([]byte(`{(3,4),(1,2);(5,6),(0,0)}`)), ShouldBeNil)
+			So(b, ShouldResemble, boxes)
+
+			var c CircleArray
+			So(c.Scan(`{"<(1,2),3>"}`), ShouldBeNil)
+			So(c, ShouldResemble, CircleArray{NewCircle(Point{1, 2}, 3)})
+
+			var pa PathArray
+			So(pa.Scan(`{"[(1,2),(3,4)]","((0,0),(1,1))"}`), ShouldBeNil)
+			So(pa, ShouldResemble, PathArray{NewPath(Point{1, 2}, Point{3, 4}), NewClosedPath(Point{0, 0}, Point{1, 1})})
+		})
+
+		Convey("Arrays should scan back to the original", func() {
+			v, _ := boxes.Value()
+			var b BoxArray
+			So(b.Scan(v), ShouldBeNil)
+			So(b, ShouldResemble, boxes)
+
+			v, _ = lines.Value()
+			var l LineArray
+			So(l.Scan(v), ShouldBeNil)
+			So(l, ShouldResemble, lines)
+		})
+
+		Convey("Malformed elements should return an error", func() {
+			var s SegmentArray
+			So(s.Scan(`{"(1,2)"}`), ShouldNotBeNil)
+
+			var p PointArray
+			So(p.Scan(42), ShouldNotBeNil)
+		})
+	})
+}
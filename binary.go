@@ -0,0 +1,321 @@
+package geometry
+
+// Codecs for the postgres binary send/recv representation of the geometric
+// types, as used by the binary protocol and binary COPY.  Every coordinate is
+// a big-endian float8.  Paths and polygons are preceded by an int32 count of
+// their points, and paths by a closed flag byte before that.
+// info from postgres' src/backend/utils/adt/geo_ops.c (*_send and *_recv)
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// assert that types implement encoding.BinaryMarshaler and BinaryUnmarshaler
+var _ encoding.BinaryMarshaler = Point{}
+var _ encoding.BinaryMarshaler = Vector{}
+var _ encoding.BinaryMarshaler = Segment{}
+var _ encoding.BinaryMarshaler = Box{}
+var _ encoding.BinaryMarshaler = Circle{}
+var _ encoding.BinaryMarshaler = Path{}
+var _ encoding.BinaryMarshaler = Polygon{}
+var _ encoding.BinaryMarshaler = Line{}
+
+var _ encoding.BinaryUnmarshaler = &Point{}
+var _ encoding.BinaryUnmarshaler = &Vector{}
+var _ encoding.BinaryUnmarshaler = &Segment{}
+var _ encoding.BinaryUnmarshaler = &Box{}
+var _ encoding.BinaryUnmarshaler = &Circle{}
+var _ encoding.BinaryUnmarshaler = &Path{}
+var _ encoding.BinaryUnmarshaler = &Polygon{}
+var _ encoding.BinaryUnmarshaler = &Line{}
+
+func appendFloat8(b []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+func appendPointBinary(b []byte, p Point) []byte {
+	b = appendFloat8(b, p.x)
+	b = appendFloat8(b, p.y)
+	return b
+}
+
+// Reads float8s from the start of data until it is exhausted.
+func readFloat8s(data []byte) []float64 {
+	floats := make([]float64, len(data)/8)
+	for i := range floats {
+		floats[i] = math.Float64frombits(binary.BigEndian.Uint64(data[8*i:]))
+	}
+	return floats
+}
+
+// Checks that data holds exactly the expected number of float8s, and returns them.
+// Typ is the name of the postgres type, for errors.
+func expectFloat8s(data []byte, typ string, expected int) ([]float64, error) {
+	if len(data) != 8*expected {
+		return nil, &ByteCountError{Type: typ, Want: 8 * expected, Got: len(data)}
+	}
+
+	return readFloat8s(data), nil
+}
+
+// Reads the int32 point count which leads binary paths and polygons, and the
+// points which follow it.
+func readPointsBinary(data []byte, typ string) ([]Point, error) {
+	if len(data) < 4 {
+		return nil, &ByteCountError{Type: typ, Want: 4, Got: len(data), AtLeast: true}
+	}
+
+	npts := int32(binary.BigEndian.Uint32(data))
+	data = data[4:]
+
+	if npts <= 0 {
+		return nil, &GeometryError{Type: typ, Reason: fmt.Sprintf("invalid number of points in binary data: %d", npts)}
+	}
+
+	floats, err := expectFloat8s(data, typ, 2*int(npts))
+
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, npts)
+	for i := range points {
+		points[i] = Point{x: floats[2*i], y: floats[2*i+1]}
+	}
+
+	return points, nil
+}
+
+func appendPointsBinary(b []byte, typ string, points []Point) ([]byte, error) {
+	if len(points) == 0 {
+		return nil, &GeometryError{Type: typ, Reason: "cannot encode zero points"}
+	}
+
+	b = binary.BigEndian.AppendUint32(b, uint32(len(points)))
+	for _, p := range points {
+		b = appendPointBinary(b, p)
+	}
+
+	return b, nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a point.
+func (p Point) MarshalBinary() ([]byte, error) {
+	return appendPointBinary(make([]byte, 0, 16), p), nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a point.
+func (p *Point) UnmarshalBinary(data []byte) error {
+	floats, err := expectFloat8s(data, "point", 2)
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Point: %w", err)
+	}
+
+	p.x = floats[0]
+	p.y = floats[1]
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a vector,
+// which is that of a point.
+func (v Vector) MarshalBinary() ([]byte, error) {
+	return appendPointBinary(make([]byte, 0, 16), Point(v)), nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a vector.
+func (v *Vector) UnmarshalBinary(data []byte) error {
+	floats, err := expectFloat8s(data, "point", 2)
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Vector: %w", err)
+	}
+
+	v.x = floats[0]
+	v.y = floats[1]
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of an lseg.
+func (s Segment) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 32)
+	b = appendPointBinary(b, s[0])
+	b = appendPointBinary(b, s[1])
+	return b, nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of an lseg.
+func (s *Segment) UnmarshalBinary(data []byte) error {
+	floats, err := expectFloat8s(data, "lseg", 4)
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Segment: %w", err)
+	}
+
+	s[0].x = floats[0]
+	s[0].y = floats[1]
+	s[1].x = floats[2]
+	s[1].y = floats[3]
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a box.
+func (b Box) MarshalBinary() ([]byte, error) {
+	by := make([]byte, 0, 32)
+	by = appendPointBinary(by, b[0])
+	by = appendPointBinary(by, b[1])
+	return by, nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a box.
+// Like the server, the corners are normalized as they are read.
+func (b *Box) UnmarshalBinary(data []byte) error {
+	floats, err := expectFloat8s(data, "box", 4)
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Box: %w", err)
+	}
+
+	*b = NewBox(Point{x: floats[0], y: floats[1]}, Point{x: floats[2], y: floats[3]})
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a circle.
+func (c Circle) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 24)
+	b = appendPointBinary(b, c.center)
+	b = appendFloat8(b, c.radius)
+	return b, nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a circle.
+func (c *Circle) UnmarshalBinary(data []byte) error {
+	floats, err := expectFloat8s(data, "circle", 3)
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Circle: %w", err)
+	}
+
+	if floats[2] < 0 {
+		return fmt.Errorf("Error while decoding binary data for Circle: %w", &GeometryError{Type: "circle", Reason: fmt.Sprintf("negative radius %g", floats[2])})
+	}
+
+	c.center.x = floats[0]
+	c.center.y = floats[1]
+	c.radius = floats[2]
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a path.
+func (p Path) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 5+16*len(p.point))
+
+	if p.closed {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+
+	b, err := appendPointsBinary(b, "path", p.point)
+
+	if err != nil {
+		return nil, fmt.Errorf("Error while encoding binary data for Path: %w", err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a path.
+func (p *Path) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 {
+		return fmt.Errorf("Error while decoding binary data for Path: %w", &ByteCountError{Type: "path", Want: 5, Got: len(data), AtLeast: true})
+	}
+
+	points, err := readPointsBinary(data[1:], "path")
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Path: %w", err)
+	}
+
+	p.closed = data[0] != 0
+	p.point = points
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a polygon.
+func (p Polygon) MarshalBinary() ([]byte, error) {
+	b, err := appendPointsBinary(make([]byte, 0, 4+16*len(p.point)), "polygon", p.point)
+
+	if err != nil {
+		return nil, fmt.Errorf("Error while encoding binary data for Polygon: %w", err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a polygon.
+func (p *Polygon) UnmarshalBinary(data []byte) error {
+	points, err := readPointsBinary(data, "polygon")
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Polygon: %w", err)
+	}
+
+	p.point = points
+
+	return nil
+}
+
+// ----------
+
+// MarshalBinary returns the postgres binary representation of a line, which
+// is its three coefficients {A,B,C}.
+func (l Line) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 24)
+	b = appendFloat8(b, l.a)
+	b = appendFloat8(b, l.b)
+	b = appendFloat8(b, l.c)
+	return b, nil
+}
+
+// UnmarshalBinary reads the postgres binary representation of a line.
+func (l *Line) UnmarshalBinary(data []byte) error {
+	floats, err := expectFloat8s(data, "line", 3)
+
+	if err != nil {
+		return fmt.Errorf("Error while decoding binary data for Line: %w", err)
+	}
+
+	if fpZero(floats[0]) && fpZero(floats[1]) {
+		return fmt.Errorf("Error while decoding binary data for Line: %w", &GeometryError{Type: "line", Reason: "A and B cannot both be zero"})
+	}
+
+	l.a = floats[0]
+	l.b = floats[1]
+	l.c = floats[2]
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package geometry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+
+	Convey("Given values of each type", t, func() {
+
+		p := NewPoint(1, -2)
+		v := NewVector(0.5, 1e300)
+		s := NewSegment(Point{1, 2}, Point{3, 4})
+		b := NewBox(Point{-1.2, -3.4}, Point{5.6, 7.8})
+		c := NewCircle(Point{-1.2, -3.4}, 123.456)
+		path := Path{point: []Point{{1, 2}, {3, 4}, {5, 6}}, closed: true}
+		poly := Polygon{point: []Point{{0, 0}, {0, 1}, {1, 0}}}
+
+		Convey("A point should encode as two big-endian float8s", func() {
+			data, err := p.MarshalBinary()
+			So(err, ShouldBeNil)
+			So(data, ShouldResemble, []byte{
+				0x3f, 0xf0, 0, 0, 0, 0, 0, 0,
+				0xc0, 0x00, 0, 0, 0, 0, 0, 0,
+			})
+		})
+
+		Convey("A path should encode its closed flag and point count", func() {
+			data, err := path.MarshalBinary()
+			So(err, ShouldBeNil)
+			So(len(data), ShouldEqual, 1+4+3*16)
+			So(data[:5], ShouldResemble, []byte{1, 0, 0, 0, 3})
+		})
+
+		Convey("Every type should round trip", func() {
+			var rp Point
+			data, _ := p.MarshalBinary()
+			So(rp.UnmarshalBinary(data), ShouldBeNil)
+			So(rp, ShouldResemble, p)
+
+			var rv Vector
+			data, _ = v.MarshalBinary()
+			So(rv.UnmarshalBinary(data), ShouldBeNil)
+			So(rv, ShouldResemble, v)
+
+			var rs Segment
+			data, _ = s.MarshalBinary()
+			So(rs.UnmarshalBinary(data), ShouldBeNil)
+			So(rs, ShouldResemble, s)
+
+			var rb Box
+			data, _ = b.MarshalBinary()
+			So(rb.UnmarshalBinary(data), ShouldBeNil)
+			So(rb, ShouldResemble, b)
+
+			var rc Circle
+			data, _ = c.MarshalBinary()
+			So(rc.UnmarshalBinary(data), ShouldBeNil)
+			So(rc, ShouldResemble, c)
+
+			var rpath Path
+			data, _ = path.MarshalBinary()
+			So(rpath.UnmarshalBinary(data), ShouldBeNil)
+			So(rpath, ShouldResemble, path)
+
+			var rpoly Polygon
+			data, _ = poly.MarshalBinary()
+			So(rpoly.UnmarshalBinary(data), ShouldBeNil)
+			So(rpoly, ShouldResemble, poly)
+
+			l := NewLineCoefficients(1, -1, 0.5)
+			var rl Line
+			data, _ = l.MarshalBinary()
+			So(rl.UnmarshalBinary(data), ShouldBeNil)
+			So(rl, ShouldResemble, l)
+		})
+
+		Convey("A box should be normalized as it is decoded", func() {
+			var r Box
+			data := appendPointBinary(appendPointBinary(nil, Point{1, 2}), Point{3, 4})
+			So(r.UnmarshalBinary(data), ShouldBeNil)
+			So(r, ShouldResemble, NewBox(Point{1, 2}, Point{3, 4}))
+		})
+
+		Convey("Malformed data should return an error", func() {
+			var rp Point
+			So(rp.UnmarshalBinary([]byte{1, 2, 3}), ShouldNotBeNil)
+
+			var rc Circle
+			So(rc.UnmarshalBinary(appendFloat8(appendPointBinary(nil, p), -1)), ShouldNotBeNil)
+
+			var rpath Path
+			So(rpath.UnmarshalBinary([]byte{0, 0, 0, 0, 0}), ShouldNotBeNil)
+			So(rpath.UnmarshalBinary([]byte{0, 0, 0, 0, 2, 1}), ShouldNotBeNil)
+
+			_, err := Path{}.MarshalBinary()
+			So(err, ShouldNotBeNil)
+
+			var rl Line
+			data, _ := NewLineCoefficients(0, 0, 1).MarshalBinary()
+			So(rl.UnmarshalBinary(data), ShouldNotBeNil)
+
+			// as postgres, A and B within epsilon of zero count as zero
+			data, _ = NewLineCoefficients(1e-7, -1e-7, 1).MarshalBinary()
+			So(rl.UnmarshalBinary(data), ShouldNotBeNil)
+		})
+	})
+}
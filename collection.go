@@ -0,0 +1,63 @@
+package geometry
+
+// Types for the geometries of GIS formats like WKT, which have no
+// equivalent among the postgres geometric types: polygons with holes, and
+// collections of shapes.
+
+// PolygonWithHoles is a polygon with holes cut out of it, as a POLYGON of
+// more than one ring.  The holes should lie inside the shell, and not
+// overlap each other.
+type PolygonWithHoles struct {
+	Shell Polygon
+	Holes []Polygon
+}
+
+// MultiPoint is a set of points, as a MULTIPOINT.
+type MultiPoint []Point
+
+// MultiPath is a set of paths, as a MULTILINESTRING.
+type MultiPath []Path
+
+// MultiPolygon is a set of polygons, as a MULTIPOLYGON.  Polygons without
+// holes have no Holes.
+type MultiPolygon []PolygonWithHoles
+
+// GeometryCollection is a set of geometries of any type, as a
+// GEOMETRYCOLLECTION.
+type GeometryCollection []interface{}
+
+// closes a ring, by repeating its first point at the end
+func closeRing(points []Point) []Point {
+	ring := make([]Point, len(points), len(points)+1)
+	copy(ring, points)
+	return append(ring, points[0])
+}
+
+// returns whether a ring or line string ends where it starts
+func isClosedRing(points []Point) bool {
+	return len(points) > 1 && points[0] == points[len(points)-1]
+}
+
+// the closed ring of a box's corners, running clockwise from the lower left
+// corner as ST_MakeEnvelope's does
+func boxRing(b Box) []Point {
+	hi, lo := b[0], b[1]
+	return []Point{lo, {x: lo.x, y: hi.y}, hi, {x: hi.x, y: lo.y}, lo}
+}
+
+// the path along a line string, which is closed if the line string ends at
+// its first point
+func pathFromPoints(points []Point) Path {
+	if isClosedRing(points) && len(points) > 2 {
+		return Path{point: points[:len(points)-1], closed: true}
+	}
+	return Path{point: points}
+}
+
+// a Polygon if the polygon has no holes
+func simplestPolygon(pg PolygonWithHoles) interface{} {
+	if pg.Holes != nil {
+		return pg
+	}
+	return pg.Shell
+}
@@ -0,0 +1,192 @@
+package geometry
+
+// Encoding of rows for COPY ... FROM STDIN, in either the text or binary
+// format, for loading many rows much faster than with INSERTs.  The encoded
+// stream can be sent by any driver which exposes COPY, like pgx.
+// info from http://www.postgresql.org/docs/current/static/sql-copy.html
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// CopyFormat is the format of a COPY data stream.
+type CopyFormat int
+
+const (
+	CopyText CopyFormat = iota
+	CopyBinary
+)
+
+// String returns the name of the format, as used in the COPY statement's
+// FORMAT option.
+func (f CopyFormat) String() string {
+	switch f {
+	case CopyText:
+		return "text"
+	case CopyBinary:
+		return "binary"
+	}
+	return fmt.Sprintf("CopyFormat(%d)", int(f))
+}
+
+// the signature, flags and header extension length which start binary data
+var copyBinaryHeader = []byte("PGCOPY\n\xff\r\n\x00\x00\x00\x00\x00\x00\x00\x00\x00")
+
+// CopyEncoder writes rows of geometry values as COPY data.  Every row must
+// have the same number of values, in the order of the columns named in the
+// COPY statement.
+type CopyEncoder struct {
+	w       io.Writer
+	format  CopyFormat
+	buf     []byte
+	columns int
+	rows    int64
+	closed  bool
+}
+
+// NewCopyEncoder returns an encoder writing COPY data in the given format
+// to w.
+func NewCopyEncoder(w io.Writer, format CopyFormat) *CopyEncoder {
+	return &CopyEncoder{w: w, format: format, columns: -1}
+}
+
+// Encode writes one row.  A nil value is written as NULL.
+func (e *CopyEncoder) Encode(row ...Operand) error {
+	if e.closed {
+		return fmt.Errorf("Cannot encode a row after the CopyEncoder is closed")
+	}
+
+	first := e.columns < 0
+	if first {
+		e.columns = len(row)
+		if e.format == CopyBinary {
+			e.buf = append(e.buf, copyBinaryHeader...)
+		}
+	} else if len(row) != e.columns {
+		return fmt.Errorf("Expected %d values in every row, but got %d instead", e.columns, len(row))
+	}
+
+	var err error
+
+	switch e.format {
+	case CopyText:
+		e.buf, err = appendCopyText(e.buf, row)
+	case CopyBinary:
+		e.buf, err = appendCopyBinary(e.buf, row)
+	default:
+		err = fmt.Errorf("Unknown COPY format: %s", e.format)
+	}
+
+	if err != nil {
+		// nothing was written, so a first row still has to write the header
+		e.buf = e.buf[:0]
+		if first {
+			e.columns = -1
+		}
+		return err
+	}
+
+	if _, err := e.w.Write(e.buf); err != nil {
+		return err
+	}
+
+	e.buf = e.buf[:0]
+	e.rows++
+
+	return nil
+}
+
+// Rows returns the number of rows encoded so far.
+func (e *CopyEncoder) Rows() int64 {
+	return e.rows
+}
+
+// Close ends the COPY data.  It does not close the underlying writer.
+func (e *CopyEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	if e.format != CopyBinary {
+		return nil
+	}
+
+	// binary data needs a header even without rows, and a trailer of -1
+	if e.columns < 0 {
+		e.buf = append(e.buf, copyBinaryHeader...)
+	}
+	e.buf = binary.BigEndian.AppendUint16(e.buf, 0xffff)
+
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+// Appends a row in the text format: tab separated values, with special
+// characters escaped by backslashes, and \N for NULL.
+func appendCopyText(b []byte, row []Operand) ([]byte, error) {
+	for i, v := range row {
+		if i > 0 {
+			b = append(b, '\t')
+		}
+
+		if v == nil {
+			b = append(b, '\\', 'N')
+			continue
+		}
+
+		val, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		b = appendCopyEscaped(b, val.([]byte))
+	}
+
+	return append(b, '\n'), nil
+}
+
+func appendCopyEscaped(b, text []byte) []byte {
+	for _, c := range text {
+		switch c {
+		case '\\':
+			b = append(b, '\\', '\\')
+		case '\t':
+			b = append(b, '\\', 't')
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+// Appends a row in the binary format: an int16 count of the fields, then
+// each field's int32 length and its binary representation.  NULL has a
+// length of -1.
+func appendCopyBinary(b []byte, row []Operand) ([]byte, error) {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(row)))
+
+	for _, v := range row {
+		if v == nil {
+			b = binary.BigEndian.AppendUint32(b, 0xffffffff)
+			continue
+		}
+
+		data, err := v.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+		b = append(b, data...)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,114 @@
+package geometry
+
+import (
+	"bytes"
+	"encoding/binary"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCopyEncoder(t *testing.T) {
+
+	Convey("Given some rows of geometry values", t, func() {
+		p := NewPoint(1, 2)
+		b := NewBox(Point{0, 0}, Point{2, 2})
+		pa := NewClosedPath(Point{0, 0}, Point{1, 1}, Point{1, 0})
+
+		Convey("The text format should separate values with tabs, and rows with newlines", func() {
+			var buf bytes.Buffer
+			e := NewCopyEncoder(&buf, CopyText)
+
+			So(e.Encode(p, b, pa), ShouldBeNil)
+			So(e.Encode(nil, b, nil), ShouldBeNil)
+			So(e.Close(), ShouldBeNil)
+
+			So(buf.String(), ShouldEqual, "(1,2)\t((2,2),(0,0))\t((0,0),(1,1),(1,0))\n\\N\t((2,2),(0,0))\t\\N\n")
+			So(e.Rows(), ShouldEqual, 2)
+		})
+
+		Convey("Special characters should be escaped in the text format", func() {
+			So(string(appendCopyEscaped(nil, []byte("a\\b\tc\nd\re"))), ShouldEqual, `a\\b\tc\nd\re`)
+		})
+
+		Convey("The binary format should have a header, length prefixed fields, and a trailer", func() {
+			var buf bytes.Buffer
+			e := NewCopyEncoder(&buf, CopyBinary)
+
+			So(e.Encode(p, nil), ShouldBeNil)
+			So(e.Close(), ShouldBeNil)
+
+			pb, _ := p.MarshalBinary()
+
+			var want []byte
+			want = append(want, "PGCOPY\n\xff\r\n\x00"...)
+			want = binary.BigEndian.AppendUint32(want, 0)
+			want = binary.BigEndian.AppendUint32(want, 0)
+			want = binary.BigEndian.AppendUint16(want, 2)
+			want = binary.BigEndian.AppendUint32(want, uint32(len(pb)))
+			want = append(want, pb...)
+			want = binary.BigEndian.AppendUint32(want, 0xffffffff)
+			want = binary.BigEndian.AppendUint16(want, 0xffff)
+
+			So(buf.Bytes(), ShouldResemble, want)
+		})
+
+		Convey("Binary data without rows should still have a header and trailer", func() {
+			var buf bytes.Buffer
+			e := NewCopyEncoder(&buf, CopyBinary)
+			So(e.Close(), ShouldBeNil)
+			So(buf.Len(), ShouldEqual, len(copyBinaryHeader)+2)
+		})
+
+		Convey("Rows of differing lengths should be rejected", func() {
+			var buf bytes.Buffer
+			e := NewCopyEncoder(&buf, CopyText)
+			So(e.Encode(p, b), ShouldBeNil)
+			So(e.Encode(p), ShouldNotBeNil)
+			So(e.Rows(), ShouldEqual, 1)
+		})
+
+		Convey("Values which can't be encoded should be rejected, and nothing written", func() {
+			for _, f := range []CopyFormat{CopyText, CopyBinary} {
+				var buf bytes.Buffer
+				e := NewCopyEncoder(&buf, f)
+				So(e.Encode(p, Path{}), ShouldNotBeNil)
+				So(buf.Len(), ShouldEqual, 0)
+				So(e.Rows(), ShouldEqual, 0)
+			}
+		})
+
+		Convey("A row after a rejected first row should still follow the header", func() {
+			var want bytes.Buffer
+			e := NewCopyEncoder(&want, CopyBinary)
+			So(e.Encode(p, b), ShouldBeNil)
+			So(e.Close(), ShouldBeNil)
+
+			var buf bytes.Buffer
+			e = NewCopyEncoder(&buf, CopyBinary)
+			So(e.Encode(p, Path{}), ShouldNotBeNil)
+			So(e.Encode(p, b), ShouldBeNil)
+			So(e.Close(), ShouldBeNil)
+
+			So(bytes.HasPrefix(buf.Bytes(), copyBinaryHeader), ShouldBeTrue)
+			So(buf.Bytes(), ShouldResemble, want.Bytes())
+			So(e.Rows(), ShouldEqual, 1)
+		})
+
+		Convey("A rejected first row should not fix the number of columns", func() {
+			e := NewCopyEncoder(&bytes.Buffer{}, CopyText)
+			So(e.Encode(Path{}), ShouldNotBeNil)
+			So(e.Encode(p, b), ShouldBeNil)
+		})
+
+		Convey("Rows can't be encoded after closing", func() {
+			e := NewCopyEncoder(&bytes.Buffer{}, CopyText)
+			So(e.Close(), ShouldBeNil)
+			So(e.Encode(p), ShouldNotBeNil)
+		})
+
+		Convey("Formats should be named as in the COPY statement", func() {
+			So(CopyText.String(), ShouldEqual, "text")
+			So(CopyBinary.String(), ShouldEqual, "binary")
+		})
+	})
+}
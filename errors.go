@@ -0,0 +1,101 @@
+package geometry
+
+// Errors returned while decoding the geometric types, from any of their
+// representations.  The Scan and Unmarshal methods wrap these with the name
+// of the Go type being decoded, so inspect them with errors.Is and errors.As.
+// The Type field of each is the name of the postgres type, like "lseg".
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnexpectedSource is returned by Scan when the driver sends a value
+	// of a Go type it can't decode.  Use errors.As with a *SourceError to
+	// find out what was sent.
+	ErrUnexpectedSource = errors.New("Unexpected source type")
+
+	// ErrInvalidGeometry is returned when well-formed input describes an
+	// impossible shape, like a circle with a negative radius.  Use errors.As
+	// with a *GeometryError for the details.
+	ErrInvalidGeometry = errors.New("Invalid geometry")
+)
+
+// SourceError is returned when a Scan method is given a source of the wrong
+// Go type.  It wraps ErrUnexpectedSource.
+type SourceError struct {
+	Type string
+	Want string      // the accepted Go types
+	Got  interface{} // the source which was given
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("Expected %s from driver for %s, got %T instead", e.Want, e.Type, e.Got)
+}
+
+func (e *SourceError) Unwrap() error {
+	return ErrUnexpectedSource
+}
+
+// FloatCountError is returned when a decoder is given the wrong number of
+// coordinates.  If Multiple is set, any multiple of Want would have done.
+type FloatCountError struct {
+	Type     string
+	Want     int
+	Got      int
+	Multiple bool
+}
+
+func (e *FloatCountError) Error() string {
+	if e.Multiple {
+		return fmt.Sprintf("Expected a multiple of %d floats while parsing %s, but got %d instead", e.Want, e.Type, e.Got)
+	}
+	return fmt.Sprintf("Expected %d floats while parsing %s, but got %d instead", e.Want, e.Type, e.Got)
+}
+
+// ByteCountError is returned when binary data is the wrong length.  If
+// AtLeast is set, any length from Want up would have done.
+type ByteCountError struct {
+	Type    string
+	Want    int
+	Got     int
+	AtLeast bool
+}
+
+func (e *ByteCountError) Error() string {
+	if e.AtLeast {
+		return fmt.Sprintf("Expected at least %d bytes of binary data for %s, but got %d instead", e.Want, e.Type, e.Got)
+	}
+	return fmt.Sprintf("Expected %d bytes of binary data for %s, but got %d instead", e.Want, e.Type, e.Got)
+}
+
+// SyntaxError is returned when text can't be parsed.  Reason, if set,
+// explains which part of the syntax is at fault.
+type SyntaxError struct {
+	Type   string
+	Input  string
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("Invalid input syntax for type %s (%s): %q", e.Type, e.Reason, e.Input)
+	}
+	return fmt.Sprintf("Invalid input syntax for type %s: %q", e.Type, e.Input)
+}
+
+// GeometryError is returned when input describes an impossible shape, or a
+// shape is impossible to encode.  It wraps ErrInvalidGeometry.
+type GeometryError struct {
+	Type   string
+	Reason string
+}
+
+func (e *GeometryError) Error() string {
+	return fmt.Sprintf("Invalid %s: %s", e.Type, e.Reason)
+}
+
+func (e *GeometryError) Unwrap() error {
+	return ErrInvalidGeometry
+}
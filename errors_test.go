@@ -0,0 +1,110 @@
+package geometry
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+
+	Convey("Given a source of the wrong type", t, func() {
+		var b Box
+		err := b.Scan(42)
+
+		Convey("The error should be ErrUnexpectedSource", func() {
+			So(errors.Is(err, ErrUnexpectedSource), ShouldBeTrue)
+
+			var se *SourceError
+			So(errors.As(err, &se), ShouldBeTrue)
+			So(se.Type, ShouldEqual, "box")
+			So(se.Got, ShouldEqual, 42)
+		})
+
+		Convey("Arrays and nullable types should pass it through", func() {
+			var pa PointArray
+			So(errors.Is(pa.Scan(4.2), ErrUnexpectedSource), ShouldBeTrue)
+
+			var nc NullCircle
+			So(errors.Is(nc.Scan(true), ErrUnexpectedSource), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given the wrong number of floats", t, func() {
+
+		Convey("The error should say how many were expected", func() {
+			var s Segment
+			var fce *FloatCountError
+			So(errors.As(s.Scan([]float64{1, 2, 3}), &fce), ShouldBeTrue)
+			So(*fce, ShouldResemble, FloatCountError{Type: "lseg", Want: 4, Got: 3})
+		})
+
+		Convey("Paths should expect a multiple of two", func() {
+			var p Path
+			var fce *FloatCountError
+			So(errors.As(p.Scan([]float64{1, 2, 3}), &fce), ShouldBeTrue)
+			So(*fce, ShouldResemble, FloatCountError{Type: "path", Want: 2, Got: 3, Multiple: true})
+		})
+	})
+
+	Convey("Given malformed text", t, func() {
+
+		Convey("Each decoder should return a SyntaxError", func() {
+			var se *SyntaxError
+
+			var p Point
+			So(errors.As(p.Scan("(1,2"), &se), ShouldBeTrue)
+			So(*se, ShouldResemble, SyntaxError{Type: "point", Input: "(1,2"})
+
+			var l Line
+			So(errors.As(l.Scan([]byte("{1,2}")), &se), ShouldBeTrue)
+			So(se.Type, ShouldEqual, "line")
+
+			var ba BoxArray
+			So(errors.As(ba.Scan("{{(1,2),(3,4)}}"), &se), ShouldBeTrue)
+			So(se.Type, ShouldEqual, "array")
+			So(se.Reason, ShouldNotBeEmpty)
+
+			var ca CircleArray
+			So(errors.As(ca.Scan(`{"<(1,2),3"}`), &se), ShouldBeTrue)
+			So(se.Type, ShouldEqual, "circle")
+		})
+	})
+
+	Convey("Given impossible shapes", t, func() {
+
+		Convey("Each decoder should return ErrInvalidGeometry", func() {
+			var c Circle
+			So(errors.Is(c.Scan("<(1,2),-3>"), ErrInvalidGeometry), ShouldBeTrue)
+			So(errors.Is(c.UnmarshalBinary(appendFloat8(appendPointBinary(nil, Origin), -1)), ErrInvalidGeometry), ShouldBeTrue)
+
+			var l Line
+			So(errors.Is(l.Scan("{0,0,1}"), ErrInvalidGeometry), ShouldBeTrue)
+			So(errors.Is(l.Scan("[(1,1),(1,1)]"), ErrInvalidGeometry), ShouldBeTrue)
+
+			var ge *GeometryError
+			So(errors.As(l.Scan("{0,0,1}"), &ge), ShouldBeTrue)
+			So(ge.Type, ShouldEqual, "line")
+		})
+
+		Convey("Encoders should too", func() {
+			_, err := Path{}.Value()
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			_, err = Polygon{}.MarshalBinary()
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given binary data of the wrong length", t, func() {
+		var bce *ByteCountError
+
+		var b Box
+		So(errors.As(b.UnmarshalBinary(make([]byte, 24)), &bce), ShouldBeTrue)
+		So(*bce, ShouldResemble, ByteCountError{Type: "box", Want: 32, Got: 24})
+
+		var p Path
+		So(errors.As(p.UnmarshalBinary([]byte{1, 0}), &bce), ShouldBeTrue)
+		So(*bce, ShouldResemble, ByteCountError{Type: "path", Want: 5, Got: 2, AtLeast: true})
+	})
+}
@@ -0,0 +1,253 @@
+package geometry
+
+// Versions of the geometric types for PostGIS geometry and geography
+// columns, which are read and written as Extended Well-Known Binary (EWKB).
+// EWKB is WKB with an optional SRID, flagged in the high bits of the type.
+// PostGIS sends it hex encoded in the text protocol, and accepts it the same
+// way as input.  The encoding itself is in wkb.go.
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// assert that types implement driver.Valuer
+var _ driver.Valuer = EWKBPoint{}
+var _ driver.Valuer = EWKBSegment{}
+var _ driver.Valuer = EWKBBox{}
+var _ driver.Valuer = EWKBPath{}
+var _ driver.Valuer = EWKBPolygon{}
+
+// scanEWKB decodes EWKB sent by the driver, either raw or hex encoded, and
+// checks that it is of the expected type.
+func scanEWKB(src interface{}, typ uint32) (wkbGeometry, error) {
+	var data []byte
+
+	switch s := src.(type) {
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return wkbGeometry{}, &SourceError{Type: "geometry", Want: "[]byte or string", Got: src}
+	}
+
+	// raw EWKB starts with a byte order of 0 or 1, and hex with an ASCII digit
+	if len(data) > 0 && data[0] > 1 {
+		raw := make([]byte, hex.DecodedLen(len(data)))
+		if _, err := hex.Decode(raw, data); err != nil {
+			return wkbGeometry{}, &SyntaxError{Type: "geometry", Input: string(data), Reason: "invalid hex"}
+		}
+		data = raw
+	}
+
+	g, err := decodeWKB(data, "geometry")
+	if err != nil {
+		return wkbGeometry{}, err
+	}
+
+	if g.typ != typ {
+		return wkbGeometry{}, &SyntaxError{Type: "geometry", Input: hex.EncodeToString(data),
+			Reason: fmt.Sprintf("expected %s, got %s", wkbTypeName(typ), wkbTypeName(g.typ))}
+	}
+
+	return g, nil
+}
+
+// valueEWKB encodes a geometry as hex EWKB, as PostGIS accepts for input.
+func valueEWKB(g interface{}, srid int) (driver.Value, error) {
+	raw, err := WKBEncoder{Flavor: WKBExtended, SRID: srid}.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, hex.EncodedLen(len(raw)))
+	hex.Encode(b, raw)
+	return b, nil
+}
+
+// the ring of a decoded POLYGON, which must have no holes
+func singleRing(g interface{}, typ string) (Polygon, error) {
+	switch pg := g.(type) {
+	case Polygon:
+		if len(pg.point) > 0 {
+			return pg, nil
+		}
+		return Polygon{}, &GeometryError{Type: typ, Reason: "expected a POLYGON of 1 ring, got 0"}
+	case PolygonWithHoles:
+		return Polygon{}, &GeometryError{Type: typ, Reason: fmt.Sprintf("expected a POLYGON of 1 ring, got %d", 1+len(pg.Holes))}
+	}
+	return Polygon{}, &GeometryError{Type: typ, Reason: "expected a POLYGON"}
+}
+
+// ----------
+
+// EWKBPoint is a Point in a PostGIS POINT geometry.
+type EWKBPoint struct {
+	Point Point
+	SRID  int // the spatial reference system, or zero if unknown
+}
+
+// Implements sql.Scanner interface
+func (e *EWKBPoint) Scan(src interface{}) error {
+	g, err := scanEWKB(src, wkbPoint)
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for EWKBPoint: %w", err)
+	}
+
+	e.Point = g.value.(Point)
+	e.SRID = g.srid
+
+	return nil
+}
+
+// Implements driver.Valuer interface
+func (e EWKBPoint) Value() (driver.Value, error) {
+	return valueEWKB(e.Point, e.SRID)
+}
+
+// ----------
+
+// EWKBSegment is a Segment in a PostGIS LINESTRING geometry of two points.
+type EWKBSegment struct {
+	Segment Segment
+	SRID    int // the spatial reference system, or zero if unknown
+}
+
+// Implements sql.Scanner interface
+func (e *EWKBSegment) Scan(src interface{}) error {
+	g, err := scanEWKB(src, wkbLineString)
+
+	var p Path
+	if err == nil {
+		p = g.value.(Path)
+		if n := len(p.point); p.closed || n != 2 {
+			if p.closed {
+				n++
+			}
+			err = &GeometryError{Type: "lseg", Reason: fmt.Sprintf("expected a LINESTRING of 2 points, got %d", n)}
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for EWKBSegment: %w", err)
+	}
+
+	e.Segment = NewSegment(p.point[0], p.point[1])
+	e.SRID = g.srid
+
+	return nil
+}
+
+// Implements driver.Valuer interface
+func (e EWKBSegment) Value() (driver.Value, error) {
+	return valueEWKB(e.Segment, e.SRID)
+}
+
+// ----------
+
+// EWKBBox is a Box in a PostGIS POLYGON geometry, as made by ST_MakeEnvelope.
+// Any polygon without holes can be scanned, and is read as its bounding box.
+type EWKBBox struct {
+	Box  Box
+	SRID int // the spatial reference system, or zero if unknown
+}
+
+// Implements sql.Scanner interface
+func (e *EWKBBox) Scan(src interface{}) error {
+	g, err := scanEWKB(src, wkbPolygon)
+
+	var pg Polygon
+	if err == nil {
+		pg, err = singleRing(g.value, "box")
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for EWKBBox: %w", err)
+	}
+
+	e.Box = boundingBox(pg.point)
+	e.SRID = g.srid
+
+	return nil
+}
+
+// Implements driver.Valuer interface
+func (e EWKBBox) Value() (driver.Value, error) {
+	return valueEWKB(e.Box, e.SRID)
+}
+
+// ----------
+
+// EWKBPath is a Path in a PostGIS LINESTRING geometry.  A closed path is a
+// LINESTRING which ends at its first point, so any such LINESTRING is read
+// as a closed path.
+type EWKBPath struct {
+	Path Path
+	SRID int // the spatial reference system, or zero if unknown
+}
+
+// Implements sql.Scanner interface
+func (e *EWKBPath) Scan(src interface{}) error {
+	g, err := scanEWKB(src, wkbLineString)
+
+	if err == nil && len(g.value.(Path).point) == 0 {
+		err = &GeometryError{Type: "path", Reason: "LINESTRING is empty"}
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for EWKBPath: %w", err)
+	}
+
+	e.Path = g.value.(Path)
+	e.SRID = g.srid
+
+	return nil
+}
+
+// Implements driver.Valuer interface
+func (e EWKBPath) Value() (driver.Value, error) {
+	if len(e.Path.point) == 0 {
+		return nil, &GeometryError{Type: "path", Reason: "cannot encode a path with no points"}
+	}
+
+	return valueEWKB(e.Path, e.SRID)
+}
+
+// ----------
+
+// EWKBPolygon is a Polygon in a PostGIS POLYGON geometry, without holes.
+type EWKBPolygon struct {
+	Polygon Polygon
+	SRID    int // the spatial reference system, or zero if unknown
+}
+
+// Implements sql.Scanner interface
+func (e *EWKBPolygon) Scan(src interface{}) error {
+	g, err := scanEWKB(src, wkbPolygon)
+
+	var pg Polygon
+	if err == nil {
+		pg, err = singleRing(g.value, "polygon")
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for EWKBPolygon: %w", err)
+	}
+
+	e.Polygon = pg
+	e.SRID = g.srid
+
+	return nil
+}
+
+// Implements driver.Valuer interface
+func (e EWKBPolygon) Value() (driver.Value, error) {
+	if len(e.Polygon.point) == 0 {
+		return nil, &GeometryError{Type: "polygon", Reason: "cannot encode a polygon with no points"}
+	}
+
+	return valueEWKB(e.Polygon, e.SRID)
+}
@@ -0,0 +1,142 @@
+package geometry
+
+import (
+	"database/sql/driver"
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestEWKB(t *testing.T) {
+
+	Convey("Given geometric values in PostGIS geometry columns", t, func() {
+
+		Convey("Points should be written as hex EWKB with an SRID", func() {
+			v, err := EWKBPoint{Point: NewPoint(1, 2), SRID: 4326}.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, "0101000020e6100000000000000000f03f0000000000000040")
+
+			v, err = EWKBPoint{Point: NewPoint(1, 2)}.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, "0101000000000000000000f03f0000000000000040")
+		})
+
+		Convey("Points should scan from hex, in either case, or raw EWKB in either byte order", func() {
+			var p EWKBPoint
+			So(p.Scan("0101000020E6100000000000000000F03F0000000000000040"), ShouldBeNil)
+			So(p, ShouldResemble, EWKBPoint{Point: NewPoint(1, 2), SRID: 4326})
+
+			So(p.Scan([]byte("0101000000000000000000f03f0000000000000040")), ShouldBeNil)
+			So(p, ShouldResemble, EWKBPoint{Point: NewPoint(1, 2)})
+
+			raw := []byte{0, 0x20, 0, 0, 1, 0, 0, 0x0f, 0x34, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0, 0x40, 0, 0, 0, 0, 0, 0, 0}
+			So(p.Scan(raw), ShouldBeNil)
+			So(p, ShouldResemble, EWKBPoint{Point: NewPoint(1, 2), SRID: 3892})
+		})
+
+		Convey("Segments should be LINESTRINGs of two points", func() {
+			s := EWKBSegment{Segment: NewSegment(Point{1, 2}, Point{3, 4}), SRID: 3857}
+			v, err := s.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldStartWith, "0102000020110f000002000000")
+
+			var r EWKBSegment
+			So(r.Scan(v), ShouldBeNil)
+			So(r, ShouldResemble, s)
+
+			var p EWKBPath
+			So(p.Scan(v), ShouldBeNil)
+			So(p.Path, ShouldResemble, NewPath(Point{1, 2}, Point{3, 4}))
+			So(r.Scan(mustEWKB(EWKBPath{Path: NewPath(Point{1, 2}, Point{3, 4}, Point{5, 6})})), ShouldNotBeNil)
+		})
+
+		Convey("Boxes should be rectangular POLYGONs", func() {
+			b := EWKBBox{Box: NewBox(Point{1, 2}, Point{3, 4}), SRID: 4326}
+			v, err := b.Value()
+			So(err, ShouldBeNil)
+
+			var pg EWKBPolygon
+			So(pg.Scan(v), ShouldBeNil)
+			So(pg.Polygon, ShouldResemble, NewPolygon(Point{1, 2}, Point{1, 4}, Point{3, 4}, Point{3, 2}))
+			So(pg.SRID, ShouldEqual, 4326)
+
+			var r EWKBBox
+			So(r.Scan(v), ShouldBeNil)
+			So(r, ShouldResemble, b)
+
+			So(r.Scan(mustEWKB(EWKBPolygon{Polygon: NewPolygon(Point{0, 0}, Point{2, 5}, Point{4, 1})})), ShouldBeNil)
+			So(r.Box, ShouldResemble, NewBox(Point{0, 0}, Point{4, 5}))
+		})
+
+		Convey("Closed paths should be LINESTRINGs which end at their first point", func() {
+			open := EWKBPath{Path: NewPath(Point{0, 0}, Point{1, 1}, Point{2, 0})}
+			closed := EWKBPath{Path: NewClosedPath(Point{0, 0}, Point{1, 1}, Point{2, 0}), SRID: 4326}
+
+			for _, p := range []EWKBPath{open, closed} {
+				var r EWKBPath
+				So(r.Scan(mustEWKB(p)), ShouldBeNil)
+				So(r, ShouldResemble, p)
+			}
+
+			v, err := closed.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldStartWith, "0102000020e610000004000000")
+
+			_, err = EWKBPath{}.Value()
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+
+		Convey("Polygons should be POLYGONs of one closed ring", func() {
+			pg := EWKBPolygon{Polygon: NewPolygon(Point{0, 0}, Point{0, 1}, Point{1, 0})}
+			v, err := pg.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldStartWith, "01030000000100000004000000")
+
+			var r EWKBPolygon
+			So(r.Scan(v), ShouldBeNil)
+			So(r, ShouldResemble, pg)
+
+			// POLYGON((0 0,0 4,4 4,4 0,0 0),(1 1,1 2,2 2,1 1))
+			holes, err := MarshalWKB(PolygonWithHoles{
+				Shell: NewPolygon(Point{0, 0}, Point{0, 4}, Point{4, 4}, Point{4, 0}),
+				Holes: []Polygon{NewPolygon(Point{1, 1}, Point{1, 2}, Point{2, 2})},
+			})
+			So(err, ShouldBeNil)
+			err = r.Scan(holes)
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+
+		Convey("Other geometries should not scan", func() {
+			var p EWKBPoint
+			var err error
+
+			// a LINESTRING
+			err = p.Scan(mustEWKB(EWKBSegment{}))
+			So(err, ShouldNotBeNil)
+			var se *SyntaxError
+			So(errors.As(err, &se), ShouldBeTrue)
+			So(se.Type, ShouldEqual, "geometry")
+
+			// POINT Z(1 2 3)
+			So(p.Scan("0101000080000000000000f03f00000000000000400000000000000840"), ShouldNotBeNil)
+
+			// truncated, trailing data, bad hex and bad byte order
+			So(p.Scan("0101000000000000000000f03f"), ShouldNotBeNil)
+			So(p.Scan("0101000000000000000000f03f000000000000004000"), ShouldNotBeNil)
+			So(p.Scan("0101000000zz"), ShouldNotBeNil)
+			So(p.Scan([]byte{}), ShouldNotBeNil)
+			So(p.Scan("0201000000000000000000f03f0000000000000040"), ShouldNotBeNil)
+
+			err = p.Scan(42)
+			So(errors.Is(err, ErrUnexpectedSource), ShouldBeTrue)
+		})
+	})
+}
+
+func mustEWKB(v driver.Valuer) []byte {
+	b, err := v.Value()
+	if err != nil {
+		panic(err)
+	}
+	return b.([]byte)
+}
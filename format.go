@@ -0,0 +1,283 @@
+package geometry
+
+// Formatting for the fmt package.  Without it, the unexported fields of the
+// types are printed as bare structs, as [{3 4} {1 2}] for a box.
+//
+//	%v, %s  the postgres text, as (1,2) or ((3,4),(1,2))
+//	%q      the postgres text, quoted
+//	%+v     the fields, labeled, as Box{max=(3,4) min=(1,2)}
+//	%#v     a Go expression for the value, as geometry.NewBox(...)
+//
+// A precision, as in %.3v, rounds the coordinates to that many decimals, as
+// Decimals does; without one, they are written exactly, as %#v always
+// writes them.  A width pads the text, on the left unless the - flag is
+// given.
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// formattable is implemented by every type.
+type formattable interface {
+	appendText(b []byte, prec Precision) []byte
+	appendLabeled(b []byte, prec Precision) []byte
+	appendGoSyntax(b []byte) []byte
+}
+
+// assert that types implement fmt.Formatter
+var _ fmt.Formatter = Point{}
+var _ fmt.Formatter = Vector{}
+var _ fmt.Formatter = Segment{}
+var _ fmt.Formatter = Box{}
+var _ fmt.Formatter = Circle{}
+var _ fmt.Formatter = Path{}
+var _ fmt.Formatter = Polygon{}
+var _ fmt.Formatter = Line{}
+
+// format writes v for the verb, as described above.
+func format(f fmt.State, verb rune, v formattable) {
+	var prec Precision
+	if n, ok := f.Precision(); ok {
+		prec = Decimals(n)
+	}
+
+	var b []byte
+
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		b = v.appendGoSyntax(b)
+	case verb == 'v' && f.Flag('+'):
+		b = v.appendLabeled(b, prec)
+	case verb == 'v' || verb == 's':
+		b = v.appendText(b, prec)
+	case verb == 'q':
+		b = strconv.AppendQuote(b, string(v.appendText(nil, prec)))
+	default:
+		// as fmt reports bad verbs, with the type and postgres text
+		b = append(b, "%!"...)
+		b = append(b, string(verb)...)
+		b = append(b, fmt.Sprintf("(%T=", v)...)
+		b = v.appendText(b, Precision{})
+		b = append(b, ')')
+	}
+
+	if w, ok := f.Width(); ok && w > len(b) {
+		pad := make([]byte, w-len(b))
+		for i := range pad {
+			pad[i] = ' '
+		}
+		if f.Flag('-') {
+			b = append(b, pad...)
+		} else {
+			b = append(pad, b...)
+		}
+	}
+
+	f.Write(b)
+}
+
+// appends a float as a Go expression
+func appendGoFloat(b []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(b, "math.NaN()"...)
+	case math.IsInf(f, 1):
+		return append(b, "math.Inf(1)"...)
+	case math.IsInf(f, -1):
+		return append(b, "math.Inf(-1)"...)
+	}
+	return strconv.AppendFloat(b, f, 'g', -1, 64)
+}
+
+// appends a point as a call to NewPoint
+func appendGoPoint(b []byte, p Point) []byte {
+	b = append(b, "geometry.NewPoint("...)
+	b = appendGoFloat(b, p.x)
+	b = append(b, ", "...)
+	b = appendGoFloat(b, p.y)
+	return append(b, ')')
+}
+
+// appends a call to a constructor taking points
+func appendGoPoints(b []byte, constructor string, points []Point) []byte {
+	b = append(b, "geometry."...)
+	b = append(b, constructor...)
+	b = append(b, '(')
+	for i, p := range points {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = appendGoPoint(b, p)
+	}
+	return append(b, ')')
+}
+
+// appends a list of points as [(x1,y1),...,(xn,yn)]
+func appendLabeledPoints(b []byte, points []Point, prec Precision) []byte {
+	b = append(b, '[')
+	b = appendTextPoints(b, points, prec)
+	return append(b, ']')
+}
+
+// ----------
+
+func (p Point) Format(f fmt.State, verb rune) {
+	format(f, verb, p)
+}
+
+func (p Point) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Point{x="...)
+	b = prec.appendFloat(b, p.x)
+	b = append(b, " y="...)
+	b = prec.appendFloat(b, p.y)
+	return append(b, '}')
+}
+
+func (p Point) appendGoSyntax(b []byte) []byte {
+	return appendGoPoint(b, p)
+}
+
+// ----------
+
+func (v Vector) Format(f fmt.State, verb rune) {
+	format(f, verb, v)
+}
+
+func (v Vector) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Vector{x="...)
+	b = prec.appendFloat(b, v.x)
+	b = append(b, " y="...)
+	b = prec.appendFloat(b, v.y)
+	return append(b, '}')
+}
+
+func (v Vector) appendGoSyntax(b []byte) []byte {
+	b = append(b, "geometry.NewVector("...)
+	b = appendGoFloat(b, v.x)
+	b = append(b, ", "...)
+	b = appendGoFloat(b, v.y)
+	return append(b, ')')
+}
+
+// ----------
+
+func (s Segment) Format(f fmt.State, verb rune) {
+	format(f, verb, s)
+}
+
+func (s Segment) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Segment{p1="...)
+	b = appendTextPoint(b, s[0], prec)
+	b = append(b, " p2="...)
+	b = appendTextPoint(b, s[1], prec)
+	return append(b, '}')
+}
+
+func (s Segment) appendGoSyntax(b []byte) []byte {
+	return appendGoPoints(b, "NewSegment", s[:])
+}
+
+// ----------
+
+func (b Box) Format(f fmt.State, verb rune) {
+	format(f, verb, b)
+}
+
+func (b Box) appendLabeled(by []byte, prec Precision) []byte {
+	by = append(by, "Box{max="...)
+	by = appendTextPoint(by, b[0], prec)
+	by = append(by, " min="...)
+	by = appendTextPoint(by, b[1], prec)
+	return append(by, '}')
+}
+
+func (b Box) appendGoSyntax(by []byte) []byte {
+	return appendGoPoints(by, "NewBox", b[:])
+}
+
+// ----------
+
+func (c Circle) Format(f fmt.State, verb rune) {
+	format(f, verb, c)
+}
+
+func (c Circle) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Circle{center="...)
+	b = appendTextPoint(b, c.center, prec)
+	b = append(b, " radius="...)
+	b = prec.appendFloat(b, c.radius)
+	return append(b, '}')
+}
+
+func (c Circle) appendGoSyntax(b []byte) []byte {
+	b = append(b, "geometry.NewCircle("...)
+	b = appendGoPoint(b, c.center)
+	b = append(b, ", "...)
+	b = appendGoFloat(b, c.radius)
+	return append(b, ')')
+}
+
+// ----------
+
+func (p Path) Format(f fmt.State, verb rune) {
+	format(f, verb, p)
+}
+
+func (p Path) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Path{closed="...)
+	b = strconv.AppendBool(b, p.closed)
+	b = append(b, " points="...)
+	b = appendLabeledPoints(b, p.point, prec)
+	return append(b, '}')
+}
+
+func (p Path) appendGoSyntax(b []byte) []byte {
+	if p.closed {
+		return appendGoPoints(b, "NewClosedPath", p.point)
+	}
+	return appendGoPoints(b, "NewPath", p.point)
+}
+
+// ----------
+
+func (p Polygon) Format(f fmt.State, verb rune) {
+	format(f, verb, p)
+}
+
+func (p Polygon) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Polygon{points="...)
+	b = appendLabeledPoints(b, p.point, prec)
+	return append(b, '}')
+}
+
+func (p Polygon) appendGoSyntax(b []byte) []byte {
+	return appendGoPoints(b, "NewPolygon", p.point)
+}
+
+// ----------
+
+func (l Line) Format(f fmt.State, verb rune) {
+	format(f, verb, l)
+}
+
+func (l Line) appendLabeled(b []byte, prec Precision) []byte {
+	b = append(b, "Line{a="...)
+	b = prec.appendFloat(b, l.a)
+	b = append(b, " b="...)
+	b = prec.appendFloat(b, l.b)
+	b = append(b, " c="...)
+	b = prec.appendFloat(b, l.c)
+	return append(b, '}')
+}
+
+func (l Line) appendGoSyntax(b []byte) []byte {
+	b = append(b, "geometry.NewLineCoefficients("...)
+	b = appendGoFloat(b, l.a)
+	b = append(b, ", "...)
+	b = appendGoFloat(b, l.b)
+	b = append(b, ", "...)
+	b = appendGoFloat(b, l.c)
+	return append(b, ')')
+}
@@ -0,0 +1,94 @@
+package geometry
+
+import (
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+
+	Convey("Given values of every type", t, func() {
+		tests := []struct {
+			value    interface{}
+			text     string
+			labeled  string
+			goSyntax string
+		}{
+			{NewPoint(1, 2.5), "(1,2.5)", "Point{x=1 y=2.5}", "geometry.NewPoint(1, 2.5)"},
+			{NewVector(-1, 0), "(-1,0)", "Vector{x=-1 y=0}", "geometry.NewVector(-1, 0)"},
+			{NewSegment(Point{1, 2}, Point{3, 4}), "[(1,2),(3,4)]", "Segment{p1=(1,2) p2=(3,4)}",
+				"geometry.NewSegment(geometry.NewPoint(1, 2), geometry.NewPoint(3, 4))"},
+			{NewBox(Point{1, 2}, Point{3, 4}), "((3,4),(1,2))", "Box{max=(3,4) min=(1,2)}",
+				"geometry.NewBox(geometry.NewPoint(3, 4), geometry.NewPoint(1, 2))"},
+			{NewCircle(Point{1, 2}, 3), "<(1,2),3>", "Circle{center=(1,2) radius=3}",
+				"geometry.NewCircle(geometry.NewPoint(1, 2), 3)"},
+			{NewPath(Point{0, 0}, Point{1, 1}), "[(0,0),(1,1)]", "Path{closed=false points=[(0,0),(1,1)]}",
+				"geometry.NewPath(geometry.NewPoint(0, 0), geometry.NewPoint(1, 1))"},
+			{NewClosedPath(Point{0, 0}, Point{1, 1}), "((0,0),(1,1))", "Path{closed=true points=[(0,0),(1,1)]}",
+				"geometry.NewClosedPath(geometry.NewPoint(0, 0), geometry.NewPoint(1, 1))"},
+			{NewPolygon(Point{0, 0}, Point{1, 1}, Point{2, 0}), "((0,0),(1,1),(2,0))", "Polygon{points=[(0,0),(1,1),(2,0)]}",
+				"geometry.NewPolygon(geometry.NewPoint(0, 0), geometry.NewPoint(1, 1), geometry.NewPoint(2, 0))"},
+			{NewLineCoefficients(1, -1, 0.5), "{1,-1,0.5}", "Line{a=1 b=-1 c=0.5}", "geometry.NewLineCoefficients(1, -1, 0.5)"},
+		}
+
+		Convey("%v and %s should print the postgres text", func() {
+			for _, test := range tests {
+				So(fmt.Sprintf("%v", test.value), ShouldEqual, test.text)
+				So(fmt.Sprintf("%s", test.value), ShouldEqual, test.text)
+				So(fmt.Sprint(test.value), ShouldEqual, test.text)
+			}
+		})
+
+		Convey("%+v should print the labeled fields", func() {
+			for _, test := range tests {
+				So(fmt.Sprintf("%+v", test.value), ShouldEqual, test.labeled)
+			}
+		})
+
+		Convey("%#v should print a Go expression", func() {
+			for _, test := range tests {
+				So(fmt.Sprintf("%#v", test.value), ShouldEqual, test.goSyntax)
+			}
+
+			So(fmt.Sprintf("%#v", NewPoint(math.NaN(), math.Inf(-1))), ShouldEqual, "geometry.NewPoint(math.NaN(), math.Inf(-1))")
+		})
+
+		Convey("Values inside other values should be formatted too", func() {
+			So(fmt.Sprintf("%v", []Point{{1, 2}, {3, 4}}), ShouldEqual, "[(1,2) (3,4)]")
+			So(fmt.Sprintf("%+v", struct{ B Box }{NewBox(Point{1, 2}, Point{3, 4})}), ShouldEqual, "{B:Box{max=(3,4) min=(1,2)}}")
+		})
+	})
+
+	Convey("Given a precision", t, func() {
+		c := NewCircle(Point{1.23456, 2}, 0.5)
+
+		Convey("The coordinates should be rounded to it", func() {
+			So(fmt.Sprintf("%.3v", c), ShouldEqual, "<(1.235,2),0.5>")
+			So(fmt.Sprintf("%.1s", c), ShouldEqual, "<(1.2,2),0.5>")
+			So(fmt.Sprintf("%+.2v", c), ShouldEqual, "Circle{center=(1.23,2) radius=0.5}")
+		})
+
+		Convey("Go syntax should be exact", func() {
+			So(fmt.Sprintf("%#.1v", c), ShouldEqual, "geometry.NewCircle(geometry.NewPoint(1.23456, 2), 0.5)")
+		})
+	})
+
+	Convey("Given other verbs and flags", t, func() {
+		p := NewPoint(1, 2)
+
+		Convey("Widths should pad the text", func() {
+			So(fmt.Sprintf("%8v|", p), ShouldEqual, "   (1,2)|")
+			So(fmt.Sprintf("%-8v|", p), ShouldEqual, "(1,2)   |")
+		})
+
+		Convey("%q should quote the text", func() {
+			So(fmt.Sprintf("%q", p), ShouldEqual, `"(1,2)"`)
+		})
+
+		Convey("Other verbs should be reported as bad", func() {
+			So(fmt.Sprintf("%d", p), ShouldEqual, "%!d(geometry.Point=(1,2))")
+		})
+	})
+}
@@ -0,0 +1,504 @@
+package geometry
+
+// GeoJSON, the JSON format for geometries which web mapping libraries like
+// Leaflet and Mapbox read.  Coordinates are written as [x, y], which for
+// geographic data is [longitude, latitude].  Only two dimensional positions
+// are supported.
+// info from https://datatracker.ietf.org/doc/html/rfc7946
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// GeoJSONEncoder writes GeoJSON geometry objects.  The zero GeoJSONEncoder
+// writes coordinates exactly, as MarshalGeoJSON does.
+type GeoJSONEncoder struct {
+	Precision Precision // of the coordinates, or Lossless if unset
+}
+
+// MarshalGeoJSON returns the GeoJSON geometry object of a geometry, with a
+// zero GeoJSONEncoder.
+func MarshalGeoJSON(g interface{}) ([]byte, error) {
+	return GeoJSONEncoder{}.Marshal(g)
+}
+
+// Marshal returns the GeoJSON geometry object of a geometry, which may be
+// any that MarshalWKT accepts.  A Segment or Path is written as a
+// LineString, and a Box as a Polygon.  Polygon rings are written
+// counterclockwise, and holes clockwise, as RFC 7946 requires.
+func (e GeoJSONEncoder) Marshal(g interface{}) ([]byte, error) {
+	return e.append(make([]byte, 0, 64), g)
+}
+
+// UnmarshalGeoJSON reads a GeoJSON geometry object.  The geometry is of the
+// same type as ParseWKT returns for the WKT of the same type, so a
+// LineString is a Path, and a Polygon is a Polygon or PolygonWithHoles.
+func UnmarshalGeoJSON(data []byte) (interface{}, error) {
+	var obj struct {
+		Type        string            `json:"type"`
+		Coordinates json.RawMessage   `json:"coordinates"`
+		Geometries  []json.RawMessage `json:"geometries"`
+	}
+
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, &SyntaxError{Type: "geojson", Input: string(data), Reason: err.Error()}
+	}
+
+	if obj.Type == "GeometryCollection" {
+		var gc GeometryCollection
+		for _, raw := range obj.Geometries {
+			g, err := UnmarshalGeoJSON(raw)
+			if err != nil {
+				return nil, err
+			}
+			gc = append(gc, g)
+		}
+		return gc, nil
+	}
+
+	var g interface{}
+	var err error
+
+	switch obj.Type {
+	case "Point":
+		var c []float64
+		if err = unmarshalCoordinates(obj.Coordinates, &c); err == nil {
+			g, err = geoJSONPosition(c)
+		}
+	case "LineString":
+		var c [][]float64
+		if err = unmarshalCoordinates(obj.Coordinates, &c); err == nil {
+			var points []Point
+			points, err = geoJSONPositions(c)
+			g = pathFromPoints(points)
+		}
+	case "Polygon":
+		var c [][][]float64
+		if err = unmarshalCoordinates(obj.Coordinates, &c); err == nil {
+			var pg PolygonWithHoles
+			pg, err = geoJSONPolygon(c)
+			g = simplestPolygon(pg)
+		}
+	case "MultiPoint":
+		var c [][]float64
+		if err = unmarshalCoordinates(obj.Coordinates, &c); err == nil {
+			var points []Point
+			points, err = geoJSONPositions(c)
+			g = MultiPoint(points)
+		}
+	case "MultiLineString":
+		var c [][][]float64
+		if err = unmarshalCoordinates(obj.Coordinates, &c); err == nil {
+			var mp MultiPath
+			for _, line := range c {
+				var points []Point
+				if points, err = geoJSONPositions(line); err != nil {
+					break
+				}
+				mp = append(mp, pathFromPoints(points))
+			}
+			g = mp
+		}
+	case "MultiPolygon":
+		var c [][][][]float64
+		if err = unmarshalCoordinates(obj.Coordinates, &c); err == nil {
+			var mp MultiPolygon
+			for _, rings := range c {
+				var pg PolygonWithHoles
+				if pg, err = geoJSONPolygon(rings); err != nil {
+					break
+				}
+				mp = append(mp, pg)
+			}
+			g = mp
+		}
+	case "":
+		err = fmt.Errorf("missing type")
+	default:
+		err = fmt.Errorf("unsupported geometry type %q", obj.Type)
+	}
+
+	if err != nil {
+		return nil, &SyntaxError{Type: "geojson", Input: string(data), Reason: err.Error()}
+	}
+
+	return g, nil
+}
+
+func unmarshalCoordinates(raw json.RawMessage, v interface{}) error {
+	if raw == nil {
+		return fmt.Errorf("missing coordinates")
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func geoJSONPosition(c []float64) (Point, error) {
+	if len(c) != 2 {
+		return Point{}, fmt.Errorf("positions must have 2 coordinates, got %d; only two dimensional positions are supported", len(c))
+	}
+	return Point{x: c[0], y: c[1]}, nil
+}
+
+func geoJSONPositions(cs [][]float64) ([]Point, error) {
+	var points []Point
+	for _, c := range cs {
+		p, err := geoJSONPosition(c)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func geoJSONPolygon(rings [][][]float64) (PolygonWithHoles, error) {
+	var pg PolygonWithHoles
+
+	for i, c := range rings {
+		ring, err := geoJSONPositions(c)
+		if err != nil {
+			return pg, err
+		}
+		if !isClosedRing(ring) {
+			return pg, fmt.Errorf("polygon rings must end at their first position")
+		}
+
+		if i == 0 {
+			pg.Shell = Polygon{point: ring[:len(ring)-1]}
+		} else {
+			pg.Holes = append(pg.Holes, Polygon{point: ring[:len(ring)-1]})
+		}
+	}
+
+	return pg, nil
+}
+
+func (e GeoJSONEncoder) append(b []byte, g interface{}) ([]byte, error) {
+	var err error
+
+	switch g := g.(type) {
+	case Point:
+		b = append(b, `{"type":"Point","coordinates":`...)
+		b, err = e.appendPosition(b, g)
+	case Segment:
+		b = append(b, `{"type":"LineString","coordinates":`...)
+		b, err = e.appendPositions(b, g[:])
+	case Path:
+		b = append(b, `{"type":"LineString","coordinates":`...)
+		b, err = e.appendPath(b, g)
+	case Box:
+		b = append(b, `{"type":"Polygon","coordinates":`...)
+		b, err = e.appendPolygon(b, PolygonWithHoles{Shell: Polygon{point: boxRing(g)[:4]}})
+	case Polygon:
+		b = append(b, `{"type":"Polygon","coordinates":`...)
+		b, err = e.appendPolygon(b, PolygonWithHoles{Shell: g})
+	case PolygonWithHoles:
+		b = append(b, `{"type":"Polygon","coordinates":`...)
+		b, err = e.appendPolygon(b, g)
+	case MultiPoint:
+		b = append(b, `{"type":"MultiPoint","coordinates":`...)
+		b, err = e.appendPositions(b, g)
+	case MultiPath:
+		b = append(b, `{"type":"MultiLineString","coordinates":[`...)
+		for i, p := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.appendPath(b, p); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	case MultiPolygon:
+		b = append(b, `{"type":"MultiPolygon","coordinates":[`...)
+		for i, pg := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.appendPolygon(b, pg); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	case GeometryCollection:
+		b = append(b, `{"type":"GeometryCollection","geometries":[`...)
+		for i, m := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.append(b, m); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	default:
+		return nil, fmt.Errorf("Cannot write %T as GeoJSON", g)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '}'), nil
+}
+
+func (e GeoJSONEncoder) appendPosition(b []byte, p Point) ([]byte, error) {
+	if math.IsNaN(p.x) || math.IsNaN(p.y) || math.IsInf(p.x, 0) || math.IsInf(p.y, 0) {
+		return nil, &GeometryError{Type: "point", Reason: "GeoJSON coordinates must be finite"}
+	}
+
+	b = append(b, '[')
+	b = e.Precision.appendFloat(b, p.x)
+	b = append(b, ',')
+	b = e.Precision.appendFloat(b, p.y)
+	return append(b, ']'), nil
+}
+
+func (e GeoJSONEncoder) appendPositions(b []byte, points []Point) ([]byte, error) {
+	var err error
+
+	b = append(b, '[')
+	for i, p := range points {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if b, err = e.appendPosition(b, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, ']'), nil
+}
+
+func (e GeoJSONEncoder) appendPath(b []byte, p Path) ([]byte, error) {
+	points := p.point
+	if p.closed && len(points) > 0 {
+		points = closeRing(points)
+	}
+	return e.appendPositions(b, points)
+}
+
+// Appends the rings of a polygon, closed, with the shell counterclockwise
+// and the holes clockwise.
+func (e GeoJSONEncoder) appendPolygon(b []byte, pg PolygonWithHoles) ([]byte, error) {
+	if len(pg.Shell.point) == 0 {
+		return append(b, '[', ']'), nil
+	}
+
+	var err error
+
+	b = append(b, '[')
+	for i, ring := range append([]Polygon{pg.Shell}, pg.Holes...) {
+		if len(ring.point) == 0 {
+			return nil, &GeometryError{Type: "polygon", Reason: "a hole has no points"}
+		}
+
+		// reversed from the same first point
+		points := ring.point
+		if ccw := signedArea(points) > 0; ccw != (i == 0) {
+			n := len(points)
+			points = make([]Point, n)
+			points[0] = ring.point[0]
+			for j := 1; j < n; j++ {
+				points[j] = ring.point[n-j]
+			}
+		}
+
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if b, err = e.appendPositions(b, closeRing(points)); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, ']'), nil
+}
+
+// the area of a polygon, which is positive if its points run
+// counterclockwise, and negative if clockwise
+func signedArea(points []Point) float64 {
+	area := 0.0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		area += p.x*q.y - q.x*p.y
+	}
+	return area / 2
+}
+
+// the bounding box of all of the points of the geometries, and false if
+// they have none
+func geometryBounds(gs ...interface{}) (Box, bool) {
+	var points []Point
+
+	var collect func(g interface{})
+	collect = func(g interface{}) {
+		switch g := g.(type) {
+		case Point:
+			points = append(points, g)
+		case Segment:
+			points = append(points, g[:]...)
+		case Path:
+			points = append(points, g.point...)
+		case Box:
+			points = append(points, g[:]...)
+		case Polygon:
+			points = append(points, g.point...)
+		case PolygonWithHoles:
+			// the holes are inside the shell
+			points = append(points, g.Shell.point...)
+		case MultiPoint:
+			points = append(points, g...)
+		case MultiPath:
+			for _, p := range g {
+				points = append(points, p.point...)
+			}
+		case MultiPolygon:
+			for _, pg := range g {
+				points = append(points, pg.Shell.point...)
+			}
+		case GeometryCollection:
+			for _, m := range g {
+				collect(m)
+			}
+		}
+	}
+
+	for _, g := range gs {
+		collect(g)
+	}
+
+	if len(points) == 0 {
+		return Box{}, false
+	}
+
+	return boundingBox(points), true
+}
+
+// ----------
+
+// GeoJSONGeometry holds a geometry which is marshalled to and from JSON as
+// a GeoJSON geometry object, for use as a field of other JSON types.  A nil
+// Geometry is written as null.
+type GeoJSONGeometry struct {
+	Geometry interface{}
+}
+
+// Implements json.Marshaller interface
+func (g GeoJSONGeometry) MarshalJSON() ([]byte, error) {
+	if g.Geometry == nil {
+		return []byte("null"), nil
+	}
+	return MarshalGeoJSON(g.Geometry)
+}
+
+// Implements json.Unmarshaller interface
+func (g *GeoJSONGeometry) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		g.Geometry = nil
+		return nil
+	}
+
+	v, err := UnmarshalGeoJSON(data)
+	if err != nil {
+		return err
+	}
+
+	g.Geometry = v
+	return nil
+}
+
+// ----------
+
+// Feature is a GeoJSON Feature: a geometry with properties.
+type Feature struct {
+	ID         interface{}            // a string or number, or nil if the feature has none
+	Geometry   interface{}            // nil if the feature has no location
+	Properties map[string]interface{} // any values which encoding/json can marshal
+}
+
+// the JSON form of a Feature
+type featureJSON struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   GeoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Implements json.Marshaller interface
+func (f Feature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(featureJSON{Type: "Feature", ID: f.ID, Geometry: GeoJSONGeometry{f.Geometry}, Properties: f.Properties})
+}
+
+// Implements json.Unmarshaller interface
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	var fj featureJSON
+	if err := json.Unmarshal(data, &fj); err != nil {
+		return err
+	}
+
+	if fj.Type != "Feature" {
+		return &SyntaxError{Type: "geojson", Input: string(data), Reason: fmt.Sprintf("expected a Feature, got %q", fj.Type)}
+	}
+
+	*f = Feature{ID: fj.ID, Geometry: fj.Geometry.Geometry, Properties: fj.Properties}
+	return nil
+}
+
+// ----------
+
+// FeatureCollection is a GeoJSON FeatureCollection.  It is written with a
+// bbox enclosing all of the features' geometries, if any have one.
+type FeatureCollection struct {
+	Features []Feature
+}
+
+// the JSON form of a FeatureCollection
+type featureCollectionJSON struct {
+	Type     string    `json:"type"`
+	BBox     []float64 `json:"bbox,omitempty"`
+	Features []Feature `json:"features"`
+}
+
+// BBox returns the smallest box enclosing all of the features' geometries,
+// and false if none of them have one.
+func (fc FeatureCollection) BBox() (Box, bool) {
+	gs := make([]interface{}, len(fc.Features))
+	for i, f := range fc.Features {
+		gs[i] = f.Geometry
+	}
+	return geometryBounds(gs...)
+}
+
+// Implements json.Marshaller interface
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	fj := featureCollectionJSON{Type: "FeatureCollection", Features: fc.Features}
+
+	if fj.Features == nil {
+		fj.Features = []Feature{}
+	}
+
+	if box, ok := fc.BBox(); ok {
+		fj.BBox = []float64{box[1].x, box[1].y, box[0].x, box[0].y}
+	}
+
+	return json.Marshal(fj)
+}
+
+// Implements json.Unmarshaller interface
+func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
+	var fj featureCollectionJSON
+	if err := json.Unmarshal(data, &fj); err != nil {
+		return err
+	}
+
+	if fj.Type != "FeatureCollection" {
+		return &SyntaxError{Type: "geojson", Input: string(data), Reason: fmt.Sprintf("expected a FeatureCollection, got %q", fj.Type)}
+	}
+
+	fc.Features = fj.Features
+	return nil
+}
@@ -0,0 +1,142 @@
+package geometry
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestGeoJSON(t *testing.T) {
+
+	Convey("Given geometric values and their GeoJSON", t, func() {
+		// counterclockwise, as GeoJSON requires of shells
+		square := NewPolygon(Point{0, 0}, Point{4, 0}, Point{4, 4}, Point{0, 4})
+		hole := NewPolygon(Point{1, 1}, Point{1, 2}, Point{2, 2})
+
+		tests := []struct {
+			value   interface{}
+			geojson string
+		}{
+			{NewPoint(1, 2.5), `{"type":"Point","coordinates":[1,2.5]}`},
+			{NewPath(Point{0, 0}, Point{1, 1}), `{"type":"LineString","coordinates":[[0,0],[1,1]]}`},
+			{NewClosedPath(Point{0, 0}, Point{1, 1}, Point{2, 0}), `{"type":"LineString","coordinates":[[0,0],[1,1],[2,0],[0,0]]}`},
+			{square, `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]]]}`},
+			{PolygonWithHoles{Shell: square, Holes: []Polygon{hole}}, `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[1,2],[2,2],[1,1]]]}`},
+			{MultiPoint{{1, 2}, {3, 4}}, `{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`},
+			{MultiPath{NewPath(Point{0, 0}, Point{1, 1})}, `{"type":"MultiLineString","coordinates":[[[0,0],[1,1]]]}`},
+			{MultiPolygon{{Shell: square}}, `{"type":"MultiPolygon","coordinates":[[[[0,0],[4,0],[4,4],[0,4],[0,0]]]]}`},
+			{GeometryCollection{NewPoint(1, 2), MultiPoint{{3, 4}}}, `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"MultiPoint","coordinates":[[3,4]]}]}`},
+		}
+
+		Convey("They should be written and read back", func() {
+			for _, test := range tests {
+				b, err := MarshalGeoJSON(test.value)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, test.geojson)
+
+				g, err := UnmarshalGeoJSON([]byte(test.geojson))
+				So(err, ShouldBeNil)
+				So(g, ShouldResemble, test.value)
+			}
+		})
+
+		Convey("Segments and boxes should be written as the natural types", func() {
+			b, err := MarshalGeoJSON(NewSegment(Point{1, 2}, Point{3, 4}))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"LineString","coordinates":[[1,2],[3,4]]}`)
+
+			b, err = MarshalGeoJSON(NewBox(Point{1, 2}, Point{3, 4}))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"Polygon","coordinates":[[[1,2],[3,2],[3,4],[1,4],[1,2]]]}`)
+		})
+
+		Convey("Shells should be written counterclockwise, and holes clockwise", func() {
+			cw := NewPolygon(Point{0, 0}, Point{0, 4}, Point{4, 4}, Point{4, 0})
+			ccw := NewPolygon(Point{1, 1}, Point{2, 1}, Point{2, 2})
+
+			b, err := MarshalGeoJSON(PolygonWithHoles{Shell: cw, Holes: []Polygon{ccw}})
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[2,2],[2,1],[1,1]]]}`)
+		})
+
+		Convey("Geometries should be usable as fields of other types", func() {
+			var v struct {
+				Name     string          `json:"name"`
+				Location GeoJSONGeometry `json:"location"`
+				Area     GeoJSONGeometry `json:"area"`
+			}
+			v.Name = "stop"
+			v.Location.Geometry = NewPoint(1, 2)
+
+			b, err := json.Marshal(v)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"name":"stop","location":{"type":"Point","coordinates":[1,2]},"area":null}`)
+
+			v.Location.Geometry = nil
+			So(json.Unmarshal(b, &v), ShouldBeNil)
+			So(v.Location.Geometry, ShouldResemble, NewPoint(1, 2))
+			So(v.Area.Geometry, ShouldBeNil)
+		})
+
+		Convey("Invalid GeoJSON should return a syntax error", func() {
+			for _, s := range []string{
+				`[1,2]`,
+				`{"coordinates":[1,2]}`,
+				`{"type":"Point"}`,
+				`{"type":"Point","coordinates":[1,2,3]}`,
+				`{"type":"Point","coordinates":[[1,2]]}`,
+				`{"type":"Polygon","coordinates":[[[0,0],[0,1],[1,0]]]}`,
+				`{"type":"Circle","coordinates":[0,0]}`,
+				`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":"x"}]}`,
+			} {
+				_, err := UnmarshalGeoJSON([]byte(s))
+				So(err, ShouldNotBeNil)
+				var se *SyntaxError
+				So(errors.As(err, &se), ShouldBeTrue)
+			}
+
+			_, err := MarshalGeoJSON(NewPoint(math.NaN(), 0))
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			_, err = MarshalGeoJSON(NewCircle(Origin, 1))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given features", t, func() {
+		fc := FeatureCollection{Features: []Feature{
+			{ID: "a", Geometry: NewPoint(1, 2), Properties: map[string]interface{}{"name": "stop"}},
+			{Geometry: NewPath(Point{-3, 0}, Point{0, 5})},
+			{ID: 3.0},
+		}}
+
+		Convey("A collection should be written with a bbox of all the geometries", func() {
+			b, err := json.Marshal(fc)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"FeatureCollection","bbox":[-3,0,1,5],"features":[`+
+				`{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"stop"}},`+
+				`{"type":"Feature","geometry":{"type":"LineString","coordinates":[[-3,0],[0,5]]},"properties":null},`+
+				`{"type":"Feature","id":3,"geometry":null,"properties":null}]}`)
+
+			var r FeatureCollection
+			So(json.Unmarshal(b, &r), ShouldBeNil)
+			So(r, ShouldResemble, fc)
+		})
+
+		Convey("A collection without geometries should have no bbox", func() {
+			b, err := json.Marshal(FeatureCollection{})
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"FeatureCollection","features":[]}`)
+		})
+
+		Convey("Objects of the wrong type should not unmarshal", func() {
+			var f Feature
+			So(json.Unmarshal([]byte(`{"type":"Point","coordinates":[1,2]}`), &f), ShouldNotBeNil)
+
+			var r FeatureCollection
+			So(json.Unmarshal([]byte(`{"type":"Feature","geometry":null,"properties":null}`), &r), ShouldNotBeNil)
+		})
+	})
+}
@@ -17,9 +17,11 @@ func init() {
 	// require that some structs are Shapes
 	var c Circle
 	var b Box
+	var pg Polygon
 
 	_ = Shape(&c)
 	_ = Shape(&b)
+	_ = Shape(&pg)
 }
 
 // ----------
@@ -145,6 +147,12 @@ func (v Vector) CrossZ(v2 Vector) float64 {
 	return v.x*v2.y - v.y*v2.x
 }
 
+// ApproxEqual returns whether both components of two vectors are within
+// epsilon of each other.
+func (v Vector) ApproxEqual(v2 Vector, epsilon Tolerance) bool {
+	return Point(v).ApproxEqual(Point(v2), epsilon)
+}
+
 // AsSegment returns a segment from the origin to the vector endpoint.
 // To obtain a segment from a point other than the origin, use
 // Point.AsSegment()
@@ -192,6 +200,25 @@ func (c Circle) Box() Box {
 	return NewBox(ll, ur)
 }
 
+// Polygon returns a polygon of npts points evenly spaced around the circle,
+// starting at its leftmost point, as postgres' polygon(npts, circle) does.
+// Like postgres, it needs at least 2 points.
+func (c Circle) Polygon(npts int) (Polygon, error) {
+	if npts < 2 {
+		return Polygon{}, &GeometryError{Type: "circle", Reason: "must request at least 2 points"}
+	}
+
+	points := make([]Point, npts)
+	step := 2 * math.Pi / float64(npts)
+
+	for i := range points {
+		angle := step * float64(i)
+		points[i] = Point{x: c.center.x - c.radius*math.Cos(angle), y: c.center.y + c.radius*math.Sin(angle)}
+	}
+
+	return Polygon{point: points}, nil
+}
+
 // Perimeter returns the perimeter of the circle
 func (c Circle) Perimeter() float64 {
 	return 2 * math.Pi * c.radius
@@ -228,6 +255,153 @@ func (s Segment) Flip() Segment {
 	return NewSegment(s[1], s[0])
 }
 
+// AsLine returns the infinite line through the segment's endpoints.
+func (s Segment) AsLine() Line {
+	return NewLine(s[0], s[1])
+}
+
+// ----------
+
+// A Line is an infinite straight line on the 2D plane, described by the
+// coefficients of its equation Ax + By + C = 0.
+// It is represented in the postgres database by the <line> type.
+// Lines are immutable.  Use Values() to inspect contents.
+type Line struct {
+	a, b, c float64
+}
+
+// NewLine returns the line passing through the two given points.  The
+// coefficients are chosen as postgres would: a vertical line is -x + C = 0,
+// a horizontal one is -y + C = 0, and any other is Mx - y + C = 0 for its
+// slope M.  If the points coincide, the result is the vertical line through
+// them.
+func NewLine(p1, p2 Point) Line {
+	return lineThrough(p1, slope(p1, p2))
+}
+
+// the line through p with slope m; a port of postgres' line_construct
+func lineThrough(p Point, m float64) Line {
+	if math.IsInf(m, 0) {
+		// vertical - use "x = C"
+		return Line{a: -1, b: 0, c: p.x}
+	}
+
+	if m == 0 {
+		// horizontal - use "y = C"
+		return Line{a: 0, b: -1, c: p.y}
+	}
+
+	// use "mx - y + yinter = 0"
+	c := p.y - m*p.x
+
+	// avoid a negative zero
+	if c == 0 {
+		c = 0
+	}
+
+	return Line{a: m, b: -1, c: c}
+}
+
+// NewLineCoefficients returns the line Ax + By + C = 0.  A and B must not
+// both be zero.
+func NewLineCoefficients(a, b, c float64) Line {
+	return Line{a: a, b: b, c: c}
+}
+
+// LineAlong returns the line passing through this point in the direction of
+// the given vector.
+func (p Point) LineAlong(v Vector) Line {
+	return NewLine(p, p.Translate(v))
+}
+
+// DistanceTo returns the perpendicular distance from the line to a point.
+func (l Line) DistanceTo(p Point) float64 {
+	_, d := l.closest(p)
+	return d
+}
+
+// IsParallel returns whether the line is parallel to another line.
+// Coincident lines are parallel.
+func (l Line) IsParallel(other Line) bool {
+	_, ok := l.Intersection(other)
+	return !ok
+}
+
+// IsPerpendicular returns whether the line is perpendicular to another line.
+func (l Line) IsPerpendicular(other Line) bool {
+	switch {
+	case fpZero(l.a):
+		return fpZero(other.b)
+	case fpZero(other.a):
+		return fpZero(l.b)
+	case fpZero(l.b):
+		return fpZero(other.a)
+	case fpZero(other.b):
+		return fpZero(l.a)
+	}
+
+	return fpEq((l.a*other.a)/(l.b*other.b), -1)
+}
+
+// Intersection returns the point where the line crosses another line.  If
+// the lines are parallel, there is no single such point, and false is
+// returned.  A port of postgres' line_interpt_line.
+func (l Line) Intersection(other Line) (Point, bool) {
+	var x, y float64
+
+	switch {
+	case !fpZero(l.b):
+		if fpEq(other.a, l.a*(other.b/l.b)) {
+			return Point{}, false
+		}
+		x = (l.b*other.c - other.b*l.c) / (l.a*other.b - other.a*l.b)
+		y = -(l.a*x + l.c) / l.b
+	case !fpZero(other.b):
+		if fpEq(l.a, other.a*(l.b/other.b)) {
+			return Point{}, false
+		}
+		x = (other.b*l.c - l.b*other.c) / (other.a*l.b - l.a*other.b)
+		y = -(other.a*x + other.c) / other.b
+	default:
+		return Point{}, false
+	}
+
+	// avoid negative zeros
+	if x == 0 {
+		x = 0
+	}
+	if y == 0 {
+		y = 0
+	}
+
+	return Point{x: x, y: y}, true
+}
+
+// IntersectSegment returns the point where the line crosses a segment.  If
+// the segment does not reach the line, or lies along it, false is returned.
+// A port of postgres' lseg_interpt_line.
+func (l Line) IntersectSegment(s Segment) (Point, bool) {
+	p, ok := s.AsLine().Intersection(l)
+
+	if !ok || !s.Contains(p) {
+		return Point{}, false
+	}
+
+	// prefer an exact endpoint to one with rounding error
+	for _, end := range s {
+		if end.Same(p) {
+			return end, true
+		}
+	}
+
+	return p, true
+}
+
+// Values returns the coefficients of the line's equation Ax + By + C = 0.
+func (l Line) Values() (a, b, c float64) {
+	return l.a, l.b, l.c
+}
+
 // ----------
 
 // A Box is a rectangle on the 2D plane.
@@ -293,15 +467,172 @@ func (b Box) Contains(p Point) bool {
 
 // ----------
 
-// TODO: Paths and Polygons
-
+// A Path is a sequence of connected points on the 2D plane.
+// It is represented in the postgres database by the <path> type.
+// An open path's first and last points are not connected; a closed path's
+// are.  Paths are immutable; every method which changes a path returns a
+// new one.
 type Path struct {
 	point  []Point
 	closed bool
 }
 
+// NewPath returns an open path through the given points.
+func NewPath(points ...Point) Path {
+	return Path{point: append([]Point(nil), points...)}
+}
+
+// NewClosedPath returns a closed path through the given points, which
+// connects the last point back to the first.
+func NewClosedPath(points ...Point) Path {
+	return Path{point: append([]Point(nil), points...), closed: true}
+}
+
+// Closed returns whether the path connects its last point to its first.
+func (p Path) Closed() bool {
+	return p.closed
+}
+
+// Len returns the number of points in the path.
+func (p Path) Len() int {
+	return len(p.point)
+}
+
+// Point returns the i'th point of the path.
+func (p Path) Point(i int) Point {
+	return p.point[i]
+}
+
+// Points returns a copy of the points of the path.
+func (p Path) Points() []Point {
+	return append([]Point(nil), p.point...)
+}
+
+// Segments returns the segments connecting each point of the path to the
+// next, in order.  For a closed path, the last segment connects the last
+// point back to the first.
+func (p Path) Segments() []Segment {
+	n := len(p.point)
+	if n < 2 {
+		return nil
+	}
+
+	segments := make([]Segment, 0, n)
+	for i := 1; i < n; i++ {
+		segments = append(segments, NewSegment(p.point[i-1], p.point[i]))
+	}
+
+	if p.closed {
+		segments = append(segments, NewSegment(p.point[n-1], p.point[0]))
+	}
+
+	return segments
+}
+
+// Length returns the total length of the path's segments.
+func (p Path) Length() float64 {
+	length := float64(0)
+	for _, s := range p.Segments() {
+		length += s.Magnitude()
+	}
+	return length
+}
+
+// Append returns a new path with the given points added to the end.
+func (p Path) Append(points ...Point) Path {
+	ps := make([]Point, 0, len(p.point)+len(points))
+	ps = append(ps, p.point...)
+	ps = append(ps, points...)
+	return Path{point: ps, closed: p.closed}
+}
+
+// Insert returns a new path with the given points inserted before the i'th
+// point.  An index equal to Len() appends them.
+func (p Path) Insert(i int, points ...Point) Path {
+	ps := make([]Point, 0, len(p.point)+len(points))
+	ps = append(ps, p.point[:i]...)
+	ps = append(ps, points...)
+	ps = append(ps, p.point[i:]...)
+	return Path{point: ps, closed: p.closed}
+}
+
+// Reverse returns a new path through the same points in the opposite order.
+func (p Path) Reverse() Path {
+	n := len(p.point)
+	ps := make([]Point, n)
+	for i, pt := range p.point {
+		ps[n-1-i] = pt
+	}
+	return Path{point: ps, closed: p.closed}
+}
+
+// A Polygon is a closed figure on the 2D plane, bounded by the segments
+// connecting its points in order, and the last point back to the first.
+// It is represented in the postgres database by the <polygon> type.
+// Implements the Shape interface.
+// Polygons are immutable.
 type Polygon Path
 
+// NewPolygon returns a polygon with the given points as its vertices.
+func NewPolygon(points ...Point) Polygon {
+	return Polygon{point: append([]Point(nil), points...)}
+}
+
+// Len returns the number of vertices of the polygon.
+func (p Polygon) Len() int {
+	return len(p.point)
+}
+
+// Point returns the i'th vertex of the polygon.
+func (p Polygon) Point(i int) Point {
+	return p.point[i]
+}
+
+// Points returns a copy of the vertices of the polygon.
+func (p Polygon) Points() []Point {
+	return append([]Point(nil), p.point...)
+}
+
+// Path returns a closed path through the vertices of the polygon.
+func (p Polygon) Path() Path {
+	return NewClosedPath(p.point...)
+}
+
+// Segments returns the edges of the polygon.
+func (p Polygon) Segments() []Segment {
+	return p.Path().Segments()
+}
+
+// Box returns the smallest box which encloses the polygon.
+func (p Polygon) Box() Box {
+	return boundingBox(p.point)
+}
+
+// Area calculates the area of the polygon, using the shoelace formula.
+// The result is only meaningful for polygons whose edges do not cross.
+func (p Polygon) Area() float64 {
+	n := len(p.point)
+	sum := float64(0)
+
+	for i := range p.point {
+		j := (i + 1) % n
+		sum += p.point[i].x*p.point[j].y - p.point[j].x*p.point[i].y
+	}
+
+	return math.Abs(sum) / 2
+}
+
+// Perimeter calculates the perimeter of the polygon.
+func (p Polygon) Perimeter() float64 {
+	return p.Path().Length()
+}
+
+// Contains returns whether the given point is on or inside the polygon.
+// This is the same even-odd test as postgres' poly_contain_pt.
+func (p Polygon) Contains(pt Point) bool {
+	return len(p.point) > 0 && pointInside(pt, p.point) != 0
+}
+
 // TimeIntercept computes the interception time of two moving points.
 // Two points, s1 and s2, which have respective velocities of v1 and v2, may
 // intercept at two times, returned by this function.  Interception is defined
@@ -1,6 +1,7 @@
 package geometry
 
 import (
+	"errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"math"
 	"testing"
@@ -132,6 +133,75 @@ func TestSegment(t *testing.T) {
 	})
 }
 
+func TestLine(t *testing.T) {
+
+	Convey("Given some lines", t, func() {
+		diag := NewLine(Origin, Point{1, 1})
+		vert := NewLine(Point{2, 0}, Point{2, 5})
+		horiz := NewLine(Point{0, 3}, Point{5, 3})
+		anti := Point{0, 4}.LineAlong(Vector{1, -1})
+
+		Convey("Coefficients should match those postgres constructs", func() {
+			a, b, c := diag.Values()
+			So([]float64{a, b, c}, ShouldResemble, []float64{1, -1, 0})
+			So(vert, ShouldResemble, NewLineCoefficients(-1, 0, 2))
+			So(horiz, ShouldResemble, NewLineCoefficients(0, -1, 3))
+			So(anti, ShouldResemble, NewLineCoefficients(-1, -1, 4))
+		})
+
+		Convey("Segments and vectors should produce the same line as their points", func() {
+			So(NewSegment(Point{2, 0}, Point{2, 5}).AsLine(), ShouldResemble, vert)
+			So(Origin.LineAlong(Vector{2, 2}), ShouldResemble, diag)
+		})
+
+		Convey("Distance to a point should be perpendicular", func() {
+			So(vert.DistanceTo(Origin), ShouldEqual, float64(2))
+			So(horiz.DistanceTo(Point{7, -1}), ShouldEqual, float64(4))
+			So(diag.DistanceTo(Point{0, 2}), ShouldAlmostEqual, math.Sqrt(2))
+			So(diag.DistanceTo(Point{3, 3}), ShouldEqual, float64(0))
+		})
+
+		Convey("Parallel and perpendicular lines should be recognized", func() {
+			So(vert.IsParallel(NewLine(Point{-1, 0}, Point{-1, 1})), ShouldBeTrue)
+			So(diag.IsParallel(Point{0, 1}.LineAlong(Vector{3, 3})), ShouldBeTrue)
+			So(diag.IsParallel(anti), ShouldBeFalse)
+
+			So(diag.IsPerpendicular(anti), ShouldBeTrue)
+			So(vert.IsPerpendicular(horiz), ShouldBeTrue)
+			So(vert.IsPerpendicular(diag), ShouldBeFalse)
+		})
+
+		Convey("Lines should intersect where expected", func() {
+			p, ok := vert.Intersection(horiz)
+			So(ok, ShouldBeTrue)
+			So(p, ShouldResemble, Point{2, 3})
+
+			p, ok = diag.Intersection(anti)
+			So(ok, ShouldBeTrue)
+			So(p, ShouldResemble, Point{2, 2})
+
+			_, ok = diag.Intersection(diag)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Lines should intersect segments only between their endpoints", func() {
+			p, ok := diag.IntersectSegment(NewSegment(Point{0, 4}, Point{4, 0}))
+			So(ok, ShouldBeTrue)
+			So(p, ShouldResemble, Point{2, 2})
+
+			p, ok = horiz.IntersectSegment(NewSegment(Point{1, 1}, Point{1, 3}))
+			So(ok, ShouldBeTrue)
+			So(p, ShouldResemble, Point{1, 3})
+
+			_, ok = horiz.IntersectSegment(NewSegment(Point{1, 1}, Point{1, 2}))
+			So(ok, ShouldBeFalse)
+
+			_, ok = horiz.IntersectSegment(NewSegment(Point{1, 3}, Point{4, 3}))
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
 func TestCircle(t *testing.T) {
 
 	Convey("Given a circle", t, func() {
@@ -160,6 +230,26 @@ func TestCircle(t *testing.T) {
 			So(c1.Box(), ShouldResemble, b1)
 		})
 
+		Convey("Its polygonal approximation should start at the leftmost point", func() {
+			pg, err := c1.Polygon(4)
+			So(err, ShouldBeNil)
+			So(pg.Len(), ShouldEqual, 4)
+			So(pg.Point(0), ShouldResemble, NewPoint(-8, 4))
+			So(pg.Point(1).ApproxEqual(NewPoint(-3, 9), PostgresEpsilon), ShouldBeTrue)
+			So(pg.Point(2).ApproxEqual(NewPoint(2, 4), PostgresEpsilon), ShouldBeTrue)
+			So(pg.Point(3).ApproxEqual(NewPoint(-3, -1), PostgresEpsilon), ShouldBeTrue)
+			So(pg.Box().ApproxEqual(b1, PostgresEpsilon), ShouldBeTrue)
+
+			pg, err = c1.Polygon(2)
+			So(err, ShouldBeNil)
+			So(pg.Len(), ShouldEqual, 2)
+
+			for _, n := range []int{1, 0, -1} {
+				_, err := c1.Polygon(n)
+				So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+			}
+		})
+
 		Convey("Values should extract correctly", func() {
 			x, y, r := c1.Values()
 			So(x, ShouldEqual, -3)
@@ -214,6 +304,115 @@ func TestBox(t *testing.T) {
 	})
 }
 
+func TestPath(t *testing.T) {
+
+	Convey("Given open and closed paths", t, func() {
+		p1 := NewPoint(0, 0)
+		p2 := NewPoint(3, 0)
+		p3 := NewPoint(3, 4)
+		open := NewPath(p1, p2, p3)
+		closed := NewClosedPath(p1, p2, p3)
+
+		Convey("Accessors should return the path's contents", func() {
+			So(open.Closed(), ShouldBeFalse)
+			So(closed.Closed(), ShouldBeTrue)
+			So(open.Len(), ShouldEqual, 3)
+			So(open.Point(1), ShouldResemble, p2)
+			So(open.Points(), ShouldResemble, []Point{p1, p2, p3})
+		})
+
+		Convey("Paths should not share their points with callers", func() {
+			ps := []Point{p1, p2}
+			path := NewPath(ps...)
+			ps[0] = p3
+			So(path.Point(0), ShouldResemble, p1)
+
+			path.Points()[0] = p3
+			So(path.Point(0), ShouldResemble, p1)
+		})
+
+		Convey("Segments should connect each point to the next", func() {
+			So(open.Segments(), ShouldResemble, []Segment{NewSegment(p1, p2), NewSegment(p2, p3)})
+			So(closed.Segments(), ShouldResemble, []Segment{NewSegment(p1, p2), NewSegment(p2, p3), NewSegment(p3, p1)})
+			So(NewPath(p1).Segments(), ShouldBeNil)
+		})
+
+		Convey("Length should include the closing segment of a closed path", func() {
+			So(open.Length(), ShouldEqual, float64(7))
+			So(closed.Length(), ShouldEqual, float64(12))
+		})
+
+		Convey("Append, Insert and Reverse should return new paths", func() {
+			So(open.Append(p1), ShouldResemble, NewPath(p1, p2, p3, p1))
+			So(closed.Insert(1, p3), ShouldResemble, NewClosedPath(p1, p3, p2, p3))
+			So(open.Insert(3, p1), ShouldResemble, open.Append(p1))
+			So(open.Reverse(), ShouldResemble, NewPath(p3, p2, p1))
+			So(open, ShouldResemble, NewPath(p1, p2, p3))
+		})
+	})
+}
+
+func TestPolygon(t *testing.T) {
+
+	Convey("Given a square and a concave polygon", t, func() {
+		square := NewPolygon(Point{0, 0}, Point{0, 4}, Point{4, 4}, Point{4, 0})
+		// a "U" shape, open at the top
+		u := NewPolygon(Point{0, 0}, Point{0, 3}, Point{1, 3}, Point{1, 1}, Point{2, 1}, Point{2, 3}, Point{3, 3}, Point{3, 0})
+
+		Convey("Perimeter and area should be calculated correctly", func() {
+			So(square.Area(), ShouldEqual, float64(16))
+			So(square.Perimeter(), ShouldEqual, float64(16))
+			So(u.Area(), ShouldEqual, float64(7))
+			So(u.Perimeter(), ShouldEqual, float64(16))
+		})
+
+		Convey("Area should not depend on the winding direction", func() {
+			So(NewPolygon(square.Path().Reverse().Points()...).Area(), ShouldEqual, float64(16))
+		})
+
+		Convey("Its bounding box should be correct", func() {
+			So(u.Box(), ShouldResemble, NewBox(Point{0, 0}, Point{3, 3}))
+		})
+
+		Convey("Its edges should close back to the first vertex", func() {
+			edges := square.Segments()
+			So(len(edges), ShouldEqual, 4)
+			So(edges[3], ShouldResemble, NewSegment(Point{4, 0}, Point{0, 0}))
+		})
+
+		Convey("It should contain interior and boundary points", func() {
+			So(square.Contains(Point{2, 2}), ShouldBeTrue)
+			So(square.Contains(Point{0, 0}), ShouldBeTrue)
+			So(square.Contains(Point{4, 4}), ShouldBeTrue)
+			So(square.Contains(Point{0, 2}), ShouldBeTrue)
+			So(square.Contains(Point{2, 4}), ShouldBeTrue)
+			So(square.Contains(Point{4, 2}), ShouldBeTrue)
+
+			So(u.Contains(Point{0.5, 2}), ShouldBeTrue)
+			So(u.Contains(Point{2.5, 2}), ShouldBeTrue)
+			So(u.Contains(Point{1.5, 0.5}), ShouldBeTrue)
+			So(u.Contains(Point{1.5, 1}), ShouldBeTrue)
+			So(u.Contains(Point{1, 2}), ShouldBeTrue)
+		})
+
+		Convey("It should not contain exterior points", func() {
+			So(square.Contains(Point{5, 2}), ShouldBeFalse)
+			So(square.Contains(Point{-1, 4}), ShouldBeFalse)
+			So(square.Contains(Point{2, -0.1}), ShouldBeFalse)
+
+			So(u.Contains(Point{1.5, 2}), ShouldBeFalse)
+			So(u.Contains(Point{-1, 3}), ShouldBeFalse)
+			So(u.Contains(Point{4, 1}), ShouldBeFalse)
+		})
+
+		Convey("Vertices should be accessible", func() {
+			So(square.Len(), ShouldEqual, 4)
+			So(square.Point(2), ShouldResemble, Point{4, 4})
+			So(square.Points(), ShouldResemble, []Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}})
+		})
+	})
+}
+
 func TestIntercept(t *testing.T) {
 
 	Convey("Given points and velocity vectors", t, func() {
@@ -1,8 +1,9 @@
 package geometry
 
 import (
-	_ "encoding/json"
-	"strconv"
+	"bytes"
+	"encoding/json"
+	"fmt"
 )
 
 type FormatFlag byte
@@ -17,126 +18,536 @@ type JsonOptions struct {
 	Segment FormatFlag
 	Box     FormatFlag
 	Circle  FormatFlag
+
+	// Keys of the Object layouts: the x and y of points and vectors, the
+	// two points of segments, the lower left (min) and upper right (max)
+	// corners of boxes, and the center and radius of circles.  They are
+	// used both to marshal and to unmarshal.
+	PointKeys   [2]string
+	SegmentKeys [2]string
+	BoxKeys     [2]string
+	CircleKeys  [2]string
+
+	// Precision of the coordinates, or Lossless if unset
+	Precision Precision
 }
 
 var DefaultJsonOptions = JsonOptions{
-	Point:   Array,
-	Vector:  Array,
-	Segment: Compound,
-	Box:     Compound,
-	Circle:  Compound,
+	Point:       Array,
+	Vector:      Array,
+	Segment:     Compound,
+	Box:         Compound,
+	Circle:      Compound,
+	PointKeys:   [2]string{"x", "y"},
+	SegmentKeys: [2]string{"0", "1"},
+	BoxKeys:     [2]string{"1", "0"}, // min and max, stored max first
+	CircleKeys:  [2]string{"c", "r"},
 }
 
+// Options are the layouts used by the MarshalJSON methods.  Changing them
+// affects every value marshalled afterwards, in every goroutine, so it is
+// not safe while other goroutines marshal values.  To choose the layout for
+// a single call, use JsonOptions.Marshal or JsonOptions.Bind instead.
 var Options = DefaultJsonOptions
 
-func appendPoint(b []byte, p Point, style FormatFlag) []byte {
+// withDefaults returns the options with any unset layouts and keys taken
+// from DefaultJsonOptions.
+func (o JsonOptions) withDefaults() JsonOptions {
+	if o.Point == 0 {
+		o.Point = DefaultJsonOptions.Point
+	}
+	if o.Vector == 0 {
+		o.Vector = DefaultJsonOptions.Vector
+	}
+	if o.Segment == 0 {
+		o.Segment = DefaultJsonOptions.Segment
+	}
+	if o.Box == 0 {
+		o.Box = DefaultJsonOptions.Box
+	}
+	if o.Circle == 0 {
+		o.Circle = DefaultJsonOptions.Circle
+	}
+	o.PointKeys = keysOr(o.PointKeys, DefaultJsonOptions.PointKeys)
+	o.SegmentKeys = keysOr(o.SegmentKeys, DefaultJsonOptions.SegmentKeys)
+	o.BoxKeys = keysOr(o.BoxKeys, DefaultJsonOptions.BoxKeys)
+	o.CircleKeys = keysOr(o.CircleKeys, DefaultJsonOptions.CircleKeys)
+	return o
+}
+
+// boxKeys returns the keys of the corners of a box in the order they are
+// stored, with the upper right corner first.
+func (o JsonOptions) boxKeys() [2]string {
+	return [2]string{o.BoxKeys[1], o.BoxKeys[0]}
+}
+
+func keysOr(k, d [2]string) [2]string {
+	for i := range k {
+		if k[i] == "" {
+			k[i] = d[i]
+		}
+	}
+	return k
+}
+
+// Marshal returns the JSON of a Point, Vector, Segment, Box or Circle, in
+// the layout for its type.  Unset layouts are taken from DefaultJsonOptions,
+// and the global Options are not used, so it is safe to call concurrently
+// with different options.
+func (o JsonOptions) Marshal(v interface{}) ([]byte, error) {
+	o = o.withDefaults()
+	b := make([]byte, 0, 8)
+
+	switch v := v.(type) {
+	case Point:
+		return appendPoint(b, v, o.Point, o), nil
+	case Vector:
+		return appendPoint(b, Point(v), o.Vector, o), nil
+	case Segment:
+		return appendSegmentOrBox(b, v, o.Segment, o.SegmentKeys, o), nil
+	case Box:
+		return appendSegmentOrBox(b, v, o.Box, o.boxKeys(), o), nil
+	case Circle:
+		return appendCircle(b, v, o.Circle, o), nil
+	}
+
+	return nil, fmt.Errorf("Cannot marshal %T with JsonOptions", v)
+}
+
+// Bind returns the value with the options bound to it, for use as a field
+// or element of other values passed to encoding/json.
+func (o JsonOptions) Bind(v interface{}) JsonValue {
+	return JsonValue{Value: v, Options: o}
+}
+
+// JsonValue is a Point, Vector, Segment, Box or Circle, which is marshalled
+// and unmarshalled with its own options rather than the global Options.
+type JsonValue struct {
+	Value   interface{}
+	Options JsonOptions
+}
+
+// Implements json.Marshaller interface
+func (j JsonValue) MarshalJSON() ([]byte, error) {
+	return j.Options.Marshal(j.Value)
+}
+
+func appendPoint(b []byte, p Point, style FormatFlag, o JsonOptions) []byte {
 
 	switch style {
 	case Array, Compound:
 		b = append(b, '[')
-		b = strconv.AppendFloat(b, p.x, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, p.x)
 		b = append(b, ',')
-		b = strconv.AppendFloat(b, p.y, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, p.y)
 		b = append(b, ']')
 	case Object:
-		b = append(b, '{', '"', 'x', '"', ':')
-		b = strconv.AppendFloat(b, p.x, 'g', -1, 64)
-		b = append(b, ',', '"', 'y', '"', ':')
-		b = strconv.AppendFloat(b, p.y, 'g', -1, 64)
+		b = appendJSONKey(append(b, '{'), o.PointKeys[0])
+		b = o.Precision.appendFloat(b, p.x)
+		b = appendJSONKey(append(b, ','), o.PointKeys[1])
+		b = o.Precision.appendFloat(b, p.y)
 		b = append(b, '}')
 	}
 
 	return b
 }
 
-func appendSegmentOrBox(b []byte, ps [2]Point, style FormatFlag) []byte {
+func appendSegmentOrBox(b []byte, ps [2]Point, style FormatFlag, keys [2]string, o JsonOptions) []byte {
 
 	switch style {
 	case Array:
 		b = append(b, '[')
-		b = strconv.AppendFloat(b, ps[0].x, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, ps[0].x)
 		b = append(b, ',')
-		b = strconv.AppendFloat(b, ps[0].y, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, ps[0].y)
 		b = append(b, ',')
-		b = strconv.AppendFloat(b, ps[1].x, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, ps[1].x)
 		b = append(b, ',')
-		b = strconv.AppendFloat(b, ps[1].y, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, ps[1].y)
 		b = append(b, ']')
 	case Compound:
 		b = append(b, '[')
-		b = appendPoint(b, ps[0], Options.Point)
+		b = appendPoint(b, ps[0], o.Point, o)
 		b = append(b, ',')
-		b = appendPoint(b, ps[1], Options.Point)
+		b = appendPoint(b, ps[1], o.Point, o)
 		b = append(b, ']')
 	case Object:
-		b = append(b, '{', '"', '0', '"', ':')
-		b = appendPoint(b, ps[0], Options.Point)
-		b = append(b, ',', '"', '1', '"', ':')
-		b = appendPoint(b, ps[1], Options.Point)
+		b = appendJSONKey(append(b, '{'), keys[0])
+		b = appendPoint(b, ps[0], o.Point, o)
+		b = appendJSONKey(append(b, ','), keys[1])
+		b = appendPoint(b, ps[1], o.Point, o)
 		b = append(b, '}')
 	}
 
 	return b
 }
 
-func appendCircle(b []byte, c Circle, style FormatFlag) []byte {
+func appendCircle(b []byte, c Circle, style FormatFlag, o JsonOptions) []byte {
 
 	switch style {
 	case Array:
 		b = append(b, '[')
-		b = appendPoint(b, c.center, Array)
+		b = appendPoint(b, c.center, Array, o)
 		b = append(b, ',')
-		b = strconv.AppendFloat(b, c.radius, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, c.radius)
 		b = append(b, ']')
 	case Compound:
 		b = append(b, '[')
-		b = appendPoint(b, c.center, Options.Point)
+		b = appendPoint(b, c.center, o.Point, o)
 		b = append(b, ',')
-		b = strconv.AppendFloat(b, c.radius, 'g', -1, 64)
+		b = o.Precision.appendFloat(b, c.radius)
 		b = append(b, ']')
 	case Object:
-		b = append(b, '{', '"', 'c', '"', ':')
-		b = appendPoint(b, c.center, Options.Point)
-		b = append(b, ',', '"', 'r', '"', ':')
-		b = strconv.AppendFloat(b, c.radius, 'g', -1, 64)
+		b = appendJSONKey(append(b, '{'), o.CircleKeys[0])
+		b = appendPoint(b, c.center, o.Point, o)
+		b = appendJSONKey(append(b, ','), o.CircleKeys[1])
+		b = o.Precision.appendFloat(b, c.radius)
 		b = append(b, '}')
 	}
 
 	return b
 }
 
+// appends a key of a JSON object, and its colon
+func appendJSONKey(b []byte, key string) []byte {
+	k, _ := json.Marshal(key)
+	return append(append(b, k...), ':')
+}
+
 // Implements json.Marshaller interface
 func (p Point) MarshalJSON() ([]byte, error) {
-	b := make([]byte, 0, 8)
-	b = appendPoint(b, p, Options.Point)
-	return b, nil
+	return Options.Marshal(p)
 }
 
 // Implements json.Marshaller interface
 func (v Vector) MarshalJSON() ([]byte, error) {
-	b := make([]byte, 0, 8)
-	p := Point(v)
-	b = appendPoint(b, p, Options.Vector)
-	return b, nil
+	return Options.Marshal(v)
 }
 
 // Implements json.Marshaller interface
 func (b Box) MarshalJSON() ([]byte, error) {
-	bytes := make([]byte, 0, 8)
-	bytes = appendSegmentOrBox(bytes, b, Options.Box)
-	return bytes, nil
+	return Options.Marshal(b)
 }
 
 // Implements json.Marshaller interface
 func (s Segment) MarshalJSON() ([]byte, error) {
-	bytes := make([]byte, 0, 8)
-	bytes = appendSegmentOrBox(bytes, s, Options.Segment)
-	return bytes, nil
+	return Options.Marshal(s)
 }
 
 // Implements json.Marshaller interface
 func (c Circle) MarshalJSON() ([]byte, error) {
-	bytes := make([]byte, 0, 8)
-	bytes = appendCircle(bytes, c, Options.Circle)
-	return bytes, nil
+	return Options.Marshal(c)
+}
+
+// Decoding detects the layout from the JSON itself, so values in any of the
+// layouts can be read whatever the options.  The keys of the Object layouts
+// are those of the options.
+
+// Unmarshal reads the JSON of a Point, Vector, Segment, Box or Circle into
+// v, which must be a pointer to one.  JSON null leaves the value unchanged.
+// Unset keys are taken from DefaultJsonOptions, and the global Options are
+// not used.
+func (o JsonOptions) Unmarshal(data []byte, v interface{}) error {
+	switch v.(type) {
+	case *Point, *Vector, *Segment, *Box, *Circle:
+	default:
+		return fmt.Errorf("Cannot unmarshal %T with JsonOptions", v)
+	}
+
+	if _, null := jsonStart(data); null {
+		return nil
+	}
+
+	o = o.withDefaults()
+
+	switch v := v.(type) {
+	case *Point:
+		p, err := unmarshalPoint(data, "point", o)
+		if err != nil {
+			return err
+		}
+		*v = p
+	case *Vector:
+		p, err := unmarshalPoint(data, "point", o)
+		if err != nil {
+			return err
+		}
+		*v = Vector(p)
+	case *Segment:
+		ps, err := unmarshalPointPair(data, "lseg", o.SegmentKeys, o)
+		if err != nil {
+			return err
+		}
+		*v = Segment(ps)
+	case *Box:
+		ps, err := unmarshalPointPair(data, "box", o.boxKeys(), o)
+		if err != nil {
+			return err
+		}
+		*v = NewBox(ps[0], ps[1])
+	case *Circle:
+		c, err := unmarshalCircle(data, o)
+		if err != nil {
+			return err
+		}
+		*v = c
+	}
+
+	return nil
+}
+
+// Implements json.Unmarshaller interface.  Value must be a pointer to a
+// Point, Vector, Segment, Box or Circle.
+func (j *JsonValue) UnmarshalJSON(data []byte) error {
+	return j.Options.Unmarshal(data, j.Value)
+}
+
+// returns the first byte of a JSON value, and whether the value is null
+func jsonStart(data []byte) (byte, bool) {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	if len(data) == 0 {
+		return 0, false
+	}
+	return data[0], bytes.Equal(bytes.TrimRight(data, " \t\r\n"), []byte("null"))
+}
+
+func jsonError(typ string, data []byte, err error) error {
+	return &SyntaxError{Type: typ, Input: string(data), Reason: err.Error()}
+}
+
+// jsonMembers reads the values of the two keys of a JSON object, which must
+// both be present and not null.
+func jsonMembers(data []byte, typ string, keys [2]string) ([2]json.RawMessage, error) {
+	var m map[string]json.RawMessage
+	var r [2]json.RawMessage
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return r, jsonError(typ, data, err)
+	}
+
+	for i, k := range keys {
+		r[i] = m[k]
+		if _, null := jsonStart(r[i]); r[i] == nil || null {
+			return r, jsonError(typ, data, fmt.Errorf("expected %q and %q", keys[0], keys[1]))
+		}
+	}
+
+	return r, nil
+}
+
+// unmarshalPoint reads a point in the Array or Object layout.
+func unmarshalPoint(data []byte, typ string, o JsonOptions) (Point, error) {
+	start, _ := jsonStart(data)
+
+	switch start {
+	case '[':
+		var a []float64
+		if err := json.Unmarshal(data, &a); err != nil {
+			return Point{}, jsonError(typ, data, err)
+		}
+		if len(a) != 2 {
+			return Point{}, jsonError(typ, data, fmt.Errorf("expected 2 coordinates, got %d", len(a)))
+		}
+		return Point{x: a[0], y: a[1]}, nil
+	case '{':
+		m, err := jsonMembers(data, typ, o.PointKeys)
+		if err != nil {
+			return Point{}, err
+		}
+		var p Point
+		if err := json.Unmarshal(m[0], &p.x); err != nil {
+			return Point{}, jsonError(typ, data, err)
+		}
+		if err := json.Unmarshal(m[1], &p.y); err != nil {
+			return Point{}, jsonError(typ, data, err)
+		}
+		return p, nil
+	}
+
+	return Point{}, jsonError(typ, data, fmt.Errorf("expected an array or object"))
+}
+
+// unmarshalPointPair reads the two points of a segment or box, in any
+// layout: [x1,y1,x2,y2], [p1,p2] or {"0":p1,"1":p2} with the keys given.
+func unmarshalPointPair(data []byte, typ string, keys [2]string, o JsonOptions) ([2]Point, error) {
+	var ps [2]Point
+	var raw [2]json.RawMessage
+
+	start, _ := jsonStart(data)
+
+	switch start {
+	case '[':
+		var a []json.RawMessage
+		if err := json.Unmarshal(data, &a); err != nil {
+			return ps, jsonError(typ, data, err)
+		}
+
+		switch len(a) {
+		case 4:
+			var f [4]float64
+			for i := range a {
+				if err := json.Unmarshal(a[i], &f[i]); err != nil {
+					return ps, jsonError(typ, data, err)
+				}
+			}
+			return [2]Point{{x: f[0], y: f[1]}, {x: f[2], y: f[3]}}, nil
+		case 2:
+			raw = [2]json.RawMessage{a[0], a[1]}
+		default:
+			return ps, jsonError(typ, data, fmt.Errorf("expected 2 points or 4 coordinates, got %d values", len(a)))
+		}
+	case '{':
+		m, err := jsonMembers(data, typ, keys)
+		if err != nil {
+			return ps, err
+		}
+		raw = m
+	default:
+		return ps, jsonError(typ, data, fmt.Errorf("expected an array or object"))
+	}
+
+	for i := range raw {
+		p, err := unmarshalPoint(raw[i], typ, o)
+		if err != nil {
+			return ps, err
+		}
+		ps[i] = p
+	}
+
+	return ps, nil
+}
+
+// unmarshalCircle reads a circle in any layout: [center,r] or
+// {"c":center,"r":r} with the keys of the options.
+func unmarshalCircle(data []byte, o JsonOptions) (Circle, error) {
+	var center, radius json.RawMessage
+
+	start, _ := jsonStart(data)
+
+	switch start {
+	case '[':
+		var a []json.RawMessage
+		if err := json.Unmarshal(data, &a); err != nil {
+			return Circle{}, jsonError("circle", data, err)
+		}
+		if len(a) != 2 {
+			return Circle{}, jsonError("circle", data, fmt.Errorf("expected a center and radius, got %d values", len(a)))
+		}
+		center, radius = a[0], a[1]
+	case '{':
+		m, err := jsonMembers(data, "circle", o.CircleKeys)
+		if err != nil {
+			return Circle{}, err
+		}
+		center, radius = m[0], m[1]
+	default:
+		return Circle{}, jsonError("circle", data, fmt.Errorf("expected an array or object"))
+	}
+
+	var r float64
+	if err := json.Unmarshal(radius, &r); err != nil {
+		return Circle{}, jsonError("circle", data, err)
+	}
+
+	p, err := unmarshalPoint(center, "circle", o)
+	if err != nil {
+		return Circle{}, err
+	}
+
+	if r < 0 {
+		return Circle{}, &GeometryError{Type: "circle", Reason: "radius cannot be negative"}
+	}
+
+	return Circle{center: p, radius: r}, nil
+}
+
+// Implements json.Unmarshaller interface, with the keys of the global
+// Options.  JSON null leaves the point unchanged.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	return Options.Unmarshal(data, p)
+}
+
+// Implements json.Unmarshaller interface, with the keys of the global
+// Options.  JSON null leaves the vector unchanged.
+func (v *Vector) UnmarshalJSON(data []byte) error {
+	return Options.Unmarshal(data, v)
+}
+
+// Implements json.Unmarshaller interface, with the keys of the global
+// Options.  JSON null leaves the segment unchanged.
+func (s *Segment) UnmarshalJSON(data []byte) error {
+	return Options.Unmarshal(data, s)
+}
+
+// Implements json.Unmarshaller interface, with the keys of the global
+// Options.  The corners may be in any order, and are normalized as NewBox
+// does.  JSON null leaves the box unchanged.
+func (b *Box) UnmarshalJSON(data []byte) error {
+	return Options.Unmarshal(data, b)
+}
+
+// Implements json.Unmarshaller interface, with the keys of the global
+// Options.  JSON null leaves the circle unchanged.
+func (c *Circle) UnmarshalJSON(data []byte) error {
+	return Options.Unmarshal(data, c)
+}
+
+// Paths and polygons have no JSON layouts of their own, and are written as
+// their postgres text.  One without points, which has no text, is written
+// as null.
+
+// Implements json.Marshaller interface
+func (p Path) MarshalJSON() ([]byte, error) {
+	if len(p.point) == 0 {
+		return []byte("null"), nil
+	}
+
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// Implements json.Unmarshaller interface.  JSON null reads as a path with
+// no points.
+func (p *Path) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		*p = Path{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return jsonError("path", data, err)
+	}
+	return p.UnmarshalText([]byte(s))
+}
+
+// Implements json.Marshaller interface
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	if len(p.point) == 0 {
+		return []byte("null"), nil
+	}
+
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// Implements json.Unmarshaller interface.  JSON null reads as a polygon
+// with no points.
+func (p *Polygon) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		*p = Polygon{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return jsonError("polygon", data, err)
+	}
+	return p.UnmarshalText([]byte(s))
 }
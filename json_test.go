@@ -1,13 +1,20 @@
 package geometry
 
 import (
+	"encoding/json"
+	"errors"
 	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
 	"testing"
 )
 
 func TestMarshalPoint(t *testing.T) {
 	Convey("Given a point or vector", t, func() {
 
+		Reset(func() {
+			Options = DefaultJsonOptions
+		})
+
 		p1 := Point{1, 2}
 		p2 := Point{-1234, 5678}
 		v3 := Vector{0.23423, 32.123122256}
@@ -62,6 +69,10 @@ func TestMarshalPoint(t *testing.T) {
 
 	Convey("Given a segment or box", t, func() {
 
+		Reset(func() {
+			Options = DefaultJsonOptions
+		})
+
 		s1 := Segment{Point{1, 2}, Point{-1234, 5678}}
 		b2 := Box{Point{8451394857194, 32.123122256}, Point{0.23423, 0.00000003}}
 
@@ -126,3 +137,246 @@ func TestMarshalPoint(t *testing.T) {
 				})
 		})
 }
+
+func TestUnmarshalJSON(t *testing.T) {
+	Convey("Given values of every type", t, func() {
+
+		Reset(func() {
+			Options = DefaultJsonOptions
+		})
+
+		type shapes struct {
+			P Point
+			V Vector
+			S Segment
+			B Box
+			C Circle
+		}
+
+		v := shapes{
+			P: NewPoint(0.1+0.2, -1e-300),
+			V: NewVector(-8451394857194, 0.00000003),
+			S: NewSegment(Point{1, 2}, Point{-1234, 5678.125}),
+			B: NewBox(Point{1.0 / 3, 2}, Point{3, 4}),
+			C: NewCircle(Point{-3, 4}, 2.0/3),
+		}
+
+		Convey("They should round trip exactly through every layout", func() {
+			for _, point := range []FormatFlag{Array, Object} {
+				for _, style := range []FormatFlag{Array, Compound, Object} {
+					Options = JsonOptions{Point: point, Vector: point, Segment: style, Box: style, Circle: style}
+
+					b, err := json.Marshal(v)
+					So(err, ShouldBeNil)
+
+					// decoding does not depend on the options
+					Options = DefaultJsonOptions
+
+					var r shapes
+					So(json.Unmarshal(b, &r), ShouldBeNil)
+					So(r, ShouldResemble, v)
+				}
+			}
+		})
+
+		Convey("Boxes should be normalized", func() {
+			var b Box
+			So(json.Unmarshal([]byte(`[[3,4],[1,2]]`), &b), ShouldBeNil)
+			So(b, ShouldResemble, NewBox(Point{1, 2}, Point{3, 4}))
+
+			So(json.Unmarshal([]byte(`{"0":{"x":1,"y":4},"1":[3,2]}`), &b), ShouldBeNil)
+			So(b, ShouldResemble, NewBox(Point{1, 2}, Point{3, 4}))
+		})
+
+		Convey("Null should leave values unchanged", func() {
+			p := NewPoint(1, 2)
+			So(json.Unmarshal([]byte(`null`), &p), ShouldBeNil)
+			So(p, ShouldResemble, NewPoint(1, 2))
+		})
+
+		Convey("Malformed JSON should return a syntax error", func() {
+			for _, s := range []string{`1`, `[1]`, `[1,2,3]`, `{"x":1}`, `["1",2]`, `"(1,2)"`} {
+				var p Point
+				err := json.Unmarshal([]byte(s), &p)
+				var se *SyntaxError
+				So(errors.As(err, &se), ShouldBeTrue)
+				So(se.Type, ShouldEqual, "point")
+			}
+
+			for _, s := range []string{`[1,2,3]`, `[[1,2],[3]]`, `{"0":[1,2]}`, `[1,2,3,"4"]`} {
+				var b Box
+				So(json.Unmarshal([]byte(s), &b), ShouldNotBeNil)
+			}
+
+			for _, s := range []string{`[[1,2]]`, `{"c":[1,2]}`, `[{"x":1},2]`, `[[1,2],"r"]`} {
+				var c Circle
+				So(json.Unmarshal([]byte(s), &c), ShouldNotBeNil)
+			}
+
+			var c Circle
+			err := json.Unmarshal([]byte(`{"c":[1,2],"r":-1}`), &c)
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+	})
+}
+
+func TestJsonOptions(t *testing.T) {
+	Convey("Given options chosen for each call", t, func() {
+
+		s := NewSegment(Point{1, 2}, Point{3, 4})
+		objects := JsonOptions{Point: Object, Segment: Object}
+
+		Convey("Values should be marshalled with those options, not the global ones", func() {
+			b, err := objects.Marshal(s)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"0":{"x":1,"y":2},"1":{"x":3,"y":4}}`)
+
+			b, err = json.Marshal(s)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `[[1,2],[3,4]]`)
+		})
+
+		Convey("Unset layouts should fall back to the defaults", func() {
+			b, err := JsonOptions{}.Marshal(NewCircle(Point{1, 2}, 3))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `[[1,2],3]`)
+
+			b, err = JsonOptions{Point: Object}.Marshal(NewVector(1, 2))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `[1,2]`)
+		})
+
+		Convey("Bound values should be usable inside other values", func() {
+			b, err := json.Marshal(map[string]interface{}{
+				"a": objects.Bind(NewPoint(1, 2)),
+				"b": JsonOptions{Box: Array}.Bind(NewBox(Point{1, 2}, Point{3, 4})),
+			})
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"a":{"x":1,"y":2},"b":[3,4,1,2]}`)
+
+			_, err = json.Marshal(objects.Bind(NewLine(Point{0, 0}, Point{1, 1})))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Different options should be usable at the same time", func() {
+			results := make(chan string, 20)
+			for i := 0; i < 20; i++ {
+				o := DefaultJsonOptions
+				if i%2 == 0 {
+					o = objects
+				}
+				go func() {
+					b, _ := o.Marshal(s)
+					results <- string(b)
+				}()
+			}
+
+			counts := map[string]int{}
+			for i := 0; i < 20; i++ {
+				counts[<-results]++
+			}
+			So(counts, ShouldResemble, map[string]int{
+				`{"0":{"x":1,"y":2},"1":{"x":3,"y":4}}`: 10,
+				`[[1,2],[3,4]]`:                         10,
+			})
+		})
+
+		Convey("Keys should be used to marshal and unmarshal objects", func() {
+			api := JsonOptions{
+				Point:      Object,
+				Box:        Object,
+				Circle:     Object,
+				PointKeys:  [2]string{"lng", "lat"},
+				BoxKeys:    [2]string{"min", "max"},
+				CircleKeys: [2]string{"center", "radius"},
+			}
+
+			tests := []struct {
+				value interface{}
+				json  string
+			}{
+				{NewPoint(1, 2), `{"lng":1,"lat":2}`},
+				{NewBox(Point{1, 2}, Point{3, 4}), `{"max":{"lng":3,"lat":4},"min":{"lng":1,"lat":2}}`},
+				{NewCircle(Point{1, 2}, 3), `{"center":{"lng":1,"lat":2},"radius":3}`},
+			}
+
+			for _, test := range tests {
+				b, err := api.Marshal(test.value)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, test.json)
+
+				v := api.Bind(reflect.New(reflect.TypeOf(test.value)).Interface())
+				So(json.Unmarshal(b, &v), ShouldBeNil)
+				So(reflect.ValueOf(v.Value).Elem().Interface(), ShouldResemble, test.value)
+			}
+
+			var p Point
+			So(api.Unmarshal([]byte(`{"x":1,"y":2}`), &p), ShouldNotBeNil)
+			So(api.Unmarshal([]byte(`{"lng":1,"lat":null}`), &p), ShouldNotBeNil)
+		})
+
+		Convey("Unset keys should fall back to the defaults", func() {
+			b, err := JsonOptions{Segment: Object, Point: Object, SegmentKeys: [2]string{"from"}}.Marshal(s)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"from":{"x":1,"y":2},"1":{"x":3,"y":4}}`)
+		})
+
+		Convey("The global keys should be used by UnmarshalJSON", func() {
+			Options.PointKeys = [2]string{"lng", "lat"}
+			Reset(func() { Options = DefaultJsonOptions })
+
+			var p Point
+			So(json.Unmarshal([]byte(`{"lng":1,"lat":2}`), &p), ShouldBeNil)
+			So(p, ShouldResemble, NewPoint(1, 2))
+		})
+	})
+}
+
+func TestPathAndPolygonJSON(t *testing.T) {
+	Convey("Given a struct of zero values", t, func() {
+		var v struct {
+			Point   Point
+			Segment Segment
+			Box     Box
+			Circle  Circle
+			Path    Path
+			Polygon Polygon
+			Line    Line
+		}
+
+		Convey("It should be marshalled, with null for the paths and polygons", func() {
+			b, err := json.Marshal(v)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"Point":[0,0],"Segment":[[0,0],[0,0]],"Box":[[0,0],[0,0]],"Circle":[[0,0],0],`+
+				`"Path":null,"Polygon":null,"Line":"{0,0,0}"}`)
+		})
+	})
+
+	Convey("Given paths and polygons with points", t, func() {
+		v := struct {
+			Path    Path
+			Polygon Polygon
+		}{NewClosedPath(Point{0, 0}, Point{1, 1}), NewPolygon(Point{0, 0}, Point{1, 1}, Point{2, 0})}
+
+		Convey("They should be written as their postgres text, and read back", func() {
+			b, err := json.Marshal(v)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"Path":"((0,0),(1,1))","Polygon":"((0,0),(1,1),(2,0))"}`)
+
+			r := v
+			r.Path, r.Polygon = Path{}, Polygon{}
+			So(json.Unmarshal(b, &r), ShouldBeNil)
+			So(r, ShouldResemble, v)
+
+			So(json.Unmarshal([]byte(`{"Path":null,"Polygon":null}`), &r), ShouldBeNil)
+			So(r.Path, ShouldResemble, Path{})
+			So(r.Polygon, ShouldResemble, Polygon{})
+		})
+
+		Convey("Malformed text should not be read", func() {
+			var p Path
+			So(json.Unmarshal([]byte(`"((1,2)"`), &p), ShouldNotBeNil)
+			So(json.Unmarshal([]byte(`[1,2]`), &p), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,167 @@
+package geometry
+
+// Nearest-neighbour queries, which page through the rows of a table in order
+// of distance using the <-> operator.  With a GiST index on the column,
+// postgres reads the nearest rows straight from the index:
+//
+//	CREATE INDEX ON shops USING gist (location);
+//
+// Pages are keyed by the distance and a unique key of the last row, rather
+// than an OFFSET, so rows aren't skipped or repeated when the table changes
+// between pages.
+//
+// Each row is returned with its distance and the values of any Columns, so
+// the rows themselves need no second query:
+//
+//	n := geometry.Nearest{DB: db, Table: "shops", Column: "location", Key: "id", Columns: []string{"name"}}
+//	page, err := n.Page(ctx, here, 10, "")
+//	for _, nb := range page.Neighbors {
+//		fmt.Println(nb.Key, nb.Values[0], nb.Distance)
+//	}
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidToken is returned when a continuation token was not made by
+// Nearest.Page.
+var ErrInvalidToken = errors.New("Invalid continuation token")
+
+// Nearest finds the rows of a table whose geometry column is nearest to a
+// shape.  The column may be of any type with a <-> operator for that shape,
+// like point, box or circle.  Table, Column, Key and Columns are copied into
+// the SQL as they are, so they must never come from user input.
+type Nearest struct {
+	DB      *sql.DB
+	Table   string
+	Column  string
+	Key     string   // a unique column, which orders rows at the same distance
+	Columns []string // any other columns, or expressions, to return with each row
+}
+
+// Neighbor is a row found by Nearest, identified by its key.
+type Neighbor struct {
+	Key      string // the key column, as text
+	Distance float64
+	Values   []interface{} // of the Columns, as the driver returns them
+}
+
+// Page is one page of the rows nearest to a shape.  Next is the token for
+// the following page, or empty if this is the last.
+type Page struct {
+	Neighbors []Neighbor
+	Next      string
+}
+
+// Page returns up to limit of the rows nearest to the shape, in order of
+// distance and then key.  The token is empty for the first page, and the
+// Next token of the previous page for those after, with the same shape.
+// Rows where the column is NULL are never returned.
+func (n Nearest) Page(ctx context.Context, to Operand, limit int, token string) (Page, error) {
+	if limit <= 0 {
+		return Page{}, fmt.Errorf("Limit must be positive, got %d", limit)
+	}
+
+	query, args, err := n.query(to, limit, token)
+	if err != nil {
+		return Page{}, err
+	}
+
+	rows, err := n.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return Page{}, err
+	}
+	defer rows.Close()
+
+	page := Page{Neighbors: make([]Neighbor, 0, limit)}
+
+	for rows.Next() {
+		nb := Neighbor{Values: make([]interface{}, len(n.Columns))}
+
+		dest := []interface{}{&nb.Key, &nb.Distance}
+		for i := range nb.Values {
+			dest = append(dest, &nb.Values[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return Page{}, err
+		}
+		page.Neighbors = append(page.Neighbors, nb)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Page{}, err
+	}
+
+	// one more row than the limit is fetched, to know if there is a next page
+	if len(page.Neighbors) > limit {
+		page.Neighbors = page.Neighbors[:limit]
+		page.Next = encodeToken(page.Neighbors[limit-1])
+	}
+
+	return page, nil
+}
+
+// query returns the SQL and arguments for a page.
+func (n Nearest) query(to Operand, limit int, token string) (string, []interface{}, error) {
+	dist, args, err := DistanceOrder(n.Column, to).Build(1)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "SELECT %s::text, %s", n.Key, dist)
+	for _, col := range n.Columns {
+		fmt.Fprintf(&b, ", %s", col)
+	}
+	fmt.Fprintf(&b, " FROM %s WHERE %s IS NOT NULL", n.Table, n.Column)
+
+	if token != "" {
+		last, err := decodeToken(token)
+		if err != nil {
+			return "", nil, err
+		}
+
+		args = append(args, last.Distance, last.Key)
+		fmt.Fprintf(&b, " AND (%s, %s) > ($%d::float8, $%d)", dist, n.Key, len(args)-1, len(args))
+	}
+
+	args = append(args, limit+1)
+	fmt.Fprintf(&b, " ORDER BY %s, %s LIMIT $%d", dist, n.Key, len(args))
+
+	return b.String(), args, nil
+}
+
+// A token holds the distance and key of the last row of a page.  The
+// distance is written exactly, so that it compares equal to the server's
+// distance for the same row.
+func encodeToken(last Neighbor) string {
+	s := strconv.FormatFloat(last.Distance, 'g', -1, 64) + "," + last.Key
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func decodeToken(token string) (Neighbor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Neighbor{}, ErrInvalidToken
+	}
+
+	d, key, ok := strings.Cut(string(b), ",")
+	if !ok {
+		return Neighbor{}, ErrInvalidToken
+	}
+
+	f, err := strconv.ParseFloat(d, 64)
+	if err != nil {
+		return Neighbor{}, ErrInvalidToken
+	}
+
+	return Neighbor{Key: key, Distance: f}, nil
+}
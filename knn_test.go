@@ -0,0 +1,118 @@
+package geometry
+
+import (
+	"context"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestNearest(t *testing.T) {
+
+	Convey("Given a nearest neighbour query", t, func() {
+		n := Nearest{Table: "shops", Column: "location", Key: "id"}
+
+		Convey("The first page should be ordered by distance and key", func() {
+			sql, args, err := n.query(NewPoint(1, 2), 10, "")
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id::text, (location <-> $1::point) FROM shops WHERE location IS NOT NULL"+
+				" ORDER BY (location <-> $1::point), id LIMIT $2")
+			So(args, ShouldResemble, []interface{}{"(1,2)", 11})
+		})
+
+		Convey("Later pages should start after the last row of the previous one", func() {
+			token := encodeToken(Neighbor{Key: "42", Distance: 0.1})
+
+			sql, args, err := n.query(NewCircle(Origin, 1), 10, token)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id::text, (location <-> $1::circle) FROM shops WHERE location IS NOT NULL"+
+				" AND ((location <-> $1::circle), id) > ($2::float8, $3)"+
+				" ORDER BY (location <-> $1::circle), id LIMIT $4")
+			So(args, ShouldResemble, []interface{}{"<(0,0),1>", 0.1, "42", 11})
+		})
+
+		Convey("Other columns should be selected with each row", func() {
+			n.Columns = []string{"name", "location"}
+			sql, _, err := n.query(NewPoint(1, 2), 10, "")
+			So(err, ShouldBeNil)
+			So(sql, ShouldStartWith, "SELECT id::text, (location <-> $1::point), name, location FROM shops WHERE")
+		})
+
+		Convey("Tokens should keep distances exactly, and keys containing commas", func() {
+			for _, nb := range []Neighbor{{Key: "1"}, {Key: "a,b", Distance: 1.0 / 3}, {Key: "", Distance: 1e-300}} {
+				r, err := decodeToken(encodeToken(nb))
+				So(err, ShouldBeNil)
+				So(r, ShouldResemble, nb)
+			}
+		})
+
+		Convey("Invalid tokens and limits should return an error", func() {
+			for _, token := range []string{"!", "MS4y", "eCwx"} {
+				_, _, err := n.query(Origin, 10, token)
+				So(err, ShouldEqual, ErrInvalidToken)
+			}
+
+			_, err := n.Page(context.Background(), Origin, 0, "")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNearestDB(t *testing.T) {
+
+	Convey("Given a table of points, boxes and circles", t, func() {
+		ctx := context.Background()
+
+		// not a temporary table, which would only exist on one of the pool's connections
+		_, err := db.Exec(`DROP TABLE IF EXISTS knntest`)
+		So(err, ShouldBeNil)
+		_, err = db.Exec(`CREATE TABLE knntest (id integer PRIMARY KEY, p point, b box, c circle)`)
+		So(err, ShouldBeNil)
+
+		// ids 1 to 9, at x = 1..9; ids 10 and 11 tie at x = 5, and id 12 is NULL
+		for i := 1; i <= 9; i++ {
+			p := NewPoint(float64(i), 0)
+			_, err := db.Exec(`INSERT INTO knntest VALUES ($1, $2, $3, $4)`, i, p, NewBox(p, p), NewCircle(p, 0.5))
+			So(err, ShouldBeNil)
+		}
+		for i := 10; i <= 11; i++ {
+			p := NewPoint(5, 0)
+			_, err := db.Exec(`INSERT INTO knntest VALUES ($1, $2, $3, $4)`, i, p, NewBox(p, p), NewCircle(p, 0.5))
+			So(err, ShouldBeNil)
+		}
+		_, err = db.Exec(`INSERT INTO knntest (id) VALUES (12)`)
+		So(err, ShouldBeNil)
+
+		for _, col := range []string{"p", "b", "c"} {
+			Convey("Paging through the "+col+" column should return every row once, nearest first", func() {
+				n := Nearest{DB: db, Table: "knntest", Column: col, Key: "id", Columns: []string{"id * 10"}}
+
+				var keys []string
+				var last float64
+				token := ""
+				pages := 0
+
+				for {
+					page, err := n.Page(ctx, NewPoint(5.2, 0), 4, token)
+					So(err, ShouldBeNil)
+					pages++
+
+					for _, nb := range page.Neighbors {
+						So(nb.Distance, ShouldBeGreaterThanOrEqualTo, last)
+						last = nb.Distance
+						keys = append(keys, nb.Key)
+						So(fmt.Sprint(nb.Values[0]), ShouldEqual, nb.Key+"0")
+					}
+
+					if page.Next == "" {
+						break
+					}
+					token = page.Next
+				}
+
+				So(pages, ShouldEqual, 3)
+				So(keys, ShouldResemble, []string{"5", "10", "11", "6", "4", "7", "3", "8", "2", "9", "1"})
+			})
+		}
+	})
+}
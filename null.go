@@ -0,0 +1,411 @@
+package geometry
+
+// Nullable versions of the geometric types, for use with columns which may
+// be NULL, in the style of sql.NullFloat64.
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// assert that types implement driver.Valuer
+var _ driver.Valuer = NullPoint{}
+var _ driver.Valuer = NullVector{}
+var _ driver.Valuer = NullSegment{}
+var _ driver.Valuer = NullBox{}
+var _ driver.Valuer = NullCircle{}
+var _ driver.Valuer = NullPath{}
+var _ driver.Valuer = NullPolygon{}
+var _ driver.Valuer = NullLine{}
+
+// ----------
+
+// NullPoint represents a Point which may be null.
+type NullPoint struct {
+	Point Point
+	Valid bool // Valid is true if Point is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullPoint) Scan(src interface{}) error {
+	if src == nil {
+		n.Point, n.Valid = Point{}, false
+		return nil
+	}
+
+	err := n.Point.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullPoint) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Point.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullPoint is null.
+func (n NullPoint) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Point.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullPoint.
+func (n *NullPoint) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Point, n.Valid = Point{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Point)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullVector represents a Vector which may be null.
+type NullVector struct {
+	Vector Vector
+	Valid  bool // Valid is true if Vector is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullVector) Scan(src interface{}) error {
+	if src == nil {
+		n.Vector, n.Valid = Vector{}, false
+		return nil
+	}
+
+	err := n.Vector.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullVector) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Vector.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullVector is null.
+func (n NullVector) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Vector.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullVector.
+func (n *NullVector) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Vector, n.Valid = Vector{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Vector)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullSegment represents a Segment which may be null.
+type NullSegment struct {
+	Segment Segment
+	Valid   bool // Valid is true if Segment is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullSegment) Scan(src interface{}) error {
+	if src == nil {
+		n.Segment, n.Valid = Segment{}, false
+		return nil
+	}
+
+	err := n.Segment.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullSegment) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Segment.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullSegment is null.
+func (n NullSegment) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Segment.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullSegment.
+func (n *NullSegment) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Segment, n.Valid = Segment{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Segment)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullBox represents a Box which may be null.
+type NullBox struct {
+	Box   Box
+	Valid bool // Valid is true if Box is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullBox) Scan(src interface{}) error {
+	if src == nil {
+		n.Box, n.Valid = Box{}, false
+		return nil
+	}
+
+	err := n.Box.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullBox) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Box.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullBox is null.
+func (n NullBox) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Box.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullBox.
+func (n *NullBox) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Box, n.Valid = Box{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Box)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullCircle represents a Circle which may be null.
+type NullCircle struct {
+	Circle Circle
+	Valid  bool // Valid is true if Circle is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullCircle) Scan(src interface{}) error {
+	if src == nil {
+		n.Circle, n.Valid = Circle{}, false
+		return nil
+	}
+
+	err := n.Circle.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullCircle) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Circle.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullCircle is null.
+func (n NullCircle) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Circle.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullCircle.
+func (n *NullCircle) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Circle, n.Valid = Circle{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Circle)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullPath represents a Path which may be null.
+type NullPath struct {
+	Path  Path
+	Valid bool // Valid is true if Path is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullPath) Scan(src interface{}) error {
+	if src == nil {
+		n.Path, n.Valid = Path{}, false
+		return nil
+	}
+
+	err := n.Path.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullPath) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Path.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullPath is null.
+func (n NullPath) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Path.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullPath.
+func (n *NullPath) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Path, n.Valid = Path{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Path)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullPolygon represents a Polygon which may be null.
+type NullPolygon struct {
+	Polygon Polygon
+	Valid   bool // Valid is true if Polygon is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullPolygon) Scan(src interface{}) error {
+	if src == nil {
+		n.Polygon, n.Valid = Polygon{}, false
+		return nil
+	}
+
+	err := n.Polygon.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullPolygon) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Polygon.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullPolygon is null.
+func (n NullPolygon) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Polygon.MarshalJSON()
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullPolygon.
+func (n *NullPolygon) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Polygon, n.Valid = Polygon{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Polygon)
+	n.Valid = err == nil
+	return err
+}
+
+// ----------
+
+// NullLine represents a Line which may be null.
+type NullLine struct {
+	Line  Line
+	Valid bool // Valid is true if Line is not NULL
+}
+
+// Implements sql.Scanner interface
+func (n *NullLine) Scan(src interface{}) error {
+	if src == nil {
+		n.Line, n.Valid = Line{}, false
+		return nil
+	}
+
+	err := n.Line.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+// Implements driver.Valuer interface
+func (n NullLine) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Line.Value()
+}
+
+// Implements json.Marshaller interface.  An invalid NullLine is null.
+func (n NullLine) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Line)
+}
+
+// Implements json.Unmarshaller interface.  Null is read as an invalid
+// NullLine.
+func (n *NullLine) UnmarshalJSON(data []byte) error {
+	if _, null := jsonStart(data); null {
+		n.Line, n.Valid = Line{}, false
+		return nil
+	}
+
+	err := json.Unmarshal(data, &n.Line)
+	n.Valid = err == nil
+	return err
+}
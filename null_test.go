@@ -0,0 +1,122 @@
+package geometry
+
+import (
+	"encoding/json"
+	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
+	"testing"
+)
+
+func TestNull(t *testing.T) {
+
+	Convey("Given nullable geometric values", t, func() {
+
+		Convey("A NULL from the database should scan as invalid", func() {
+			n := NullPoint{Point: NewPoint(1, 2), Valid: true}
+			So(n.Scan(nil), ShouldBeNil)
+			So(n.Valid, ShouldBeFalse)
+			So(n.Point, ShouldResemble, Point{})
+
+			var c NullCircle
+			So(c.Scan(nil), ShouldBeNil)
+			So(c.Valid, ShouldBeFalse)
+
+			var p NullPath
+			So(p.Scan(nil), ShouldBeNil)
+			So(p.Valid, ShouldBeFalse)
+		})
+
+		Convey("Values from the database should scan as valid", func() {
+			var n NullPoint
+			So(n.Scan([]byte("(1,2)")), ShouldBeNil)
+			So(n.Valid, ShouldBeTrue)
+			So(n.Point, ShouldResemble, NewPoint(1, 2))
+
+			var b NullBox
+			So(b.Scan("(3,4),(1,2)"), ShouldBeNil)
+			So(b.Valid, ShouldBeTrue)
+			So(b.Box, ShouldResemble, NewBox(Point{1, 2}, Point{3, 4}))
+
+			var pg NullPolygon
+			So(pg.Scan("((0,0),(0,1),(1,0))"), ShouldBeNil)
+			So(pg.Valid, ShouldBeTrue)
+			So(pg.Polygon, ShouldResemble, NewPolygon(Point{0, 0}, Point{0, 1}, Point{1, 0}))
+		})
+
+		Convey("Malformed values should not scan as valid", func() {
+			var s NullSegment
+			So(s.Scan("(1,2)"), ShouldNotBeNil)
+			So(s.Valid, ShouldBeFalse)
+		})
+
+		Convey("Invalid values should be written as NULL", func() {
+			v, err := NullVector{}.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldBeNil)
+
+			v, err = NullLine{}.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldBeNil)
+		})
+
+		Convey("Valid values should be written like the wrapped type", func() {
+			c := NewCircle(Point{1, 2}, 3)
+			v1, _ := NullCircle{Circle: c, Valid: true}.Value()
+			v2, _ := c.Value()
+			So(v1, ShouldResemble, v2)
+		})
+
+		Convey("JSON should be null only when invalid", func() {
+			Options = DefaultJsonOptions
+
+			b, err := json.Marshal([]NullPoint{{}, {Point: NewPoint(1, 2), Valid: true}})
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "[null,[1,2]]")
+
+			b, err = json.Marshal(NullSegment{})
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "null")
+		})
+
+		Convey("Every type should be null when invalid", func() {
+			b, err := json.Marshal([]interface{}{NullPoint{}, NullVector{}, NullSegment{}, NullBox{}, NullCircle{}, NullPath{}, NullPolygon{}, NullLine{}})
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "[null,null,null,null,null,null,null,null]")
+		})
+
+		Convey("Every type should round trip through JSON", func() {
+			values := []interface{}{
+				&NullPoint{Point: NewPoint(1, 2), Valid: true},
+				&NullVector{Vector: NewVector(1, 2), Valid: true},
+				&NullSegment{Segment: NewSegment(Point{1, 2}, Point{3, 4}), Valid: true},
+				&NullBox{Box: NewBox(Point{1, 2}, Point{3, 4}), Valid: true},
+				&NullCircle{Circle: NewCircle(Point{1, 2}, 3), Valid: true},
+				&NullPath{Path: NewPath(Point{1, 2}, Point{3, 4}), Valid: true},
+				&NullPolygon{Polygon: NewPolygon(Point{1, 2}, Point{3, 4}, Point{5, 0}), Valid: true},
+				&NullLine{Line: NewLineCoefficients(1, -1, 0.5), Valid: true},
+				&NullPoint{},
+				&NullPath{},
+				&NullLine{},
+			}
+
+			for _, v := range values {
+				b, err := json.Marshal(v)
+				So(err, ShouldBeNil)
+
+				r := reflect.New(reflect.TypeOf(v).Elem())
+				So(json.Unmarshal(b, r.Interface()), ShouldBeNil)
+				So(r.Interface(), ShouldResemble, v)
+			}
+		})
+
+		Convey("JSON null should read as invalid", func() {
+			n := NullPolygon{Polygon: NewPolygon(Point{1, 2}), Valid: true}
+			So(json.Unmarshal([]byte("null"), &n), ShouldBeNil)
+			So(n, ShouldResemble, NullPolygon{})
+
+			var s struct{ L NullLine }
+			So(json.Unmarshal([]byte(`{"L":"{1,-1,0}"}`), &s), ShouldBeNil)
+			So(s.L, ShouldResemble, NullLine{Line: NewLineCoefficients(1, -1, 0), Valid: true})
+		})
+	})
+}
@@ -0,0 +1,1088 @@
+package geometry
+
+// Go implementations of the postgres geometric operators, giving the same
+// answers as the server so that shapes can be filtered in memory.  Each is a
+// port of the function in postgres' src/backend/utils/adt/geo_ops.c which
+// implements the operator named in its doc comment.
+// info from http://www.postgresql.org/docs/current/static/functions-geometry.html
+
+import (
+	"math"
+)
+
+// Tolerance is the largest difference between two coordinates at which the
+// predicates in this package still consider them equal.  It should be zero
+// or positive.
+type Tolerance float64
+
+const (
+	// Exact compares coordinates exactly, as Go's comparison operators do.
+	Exact Tolerance = 0
+
+	// PostgresEpsilon is the tolerance postgres compares most coordinates
+	// with, through the FPeq family of macros in src/include/utils/geo_decls.h.
+	// The predicates in the package use it wherever postgres does, from Same
+	// through to the positional operators, so that answers agree with the
+	// server's.  Where postgres compares exactly, as for a point in a box or
+	// circle and the polygon positional operators, so do these.  For another
+	// tolerance, pass it to ApproxEqual.
+	PostgresEpsilon Tolerance = 1.0e-06
+)
+
+func (t Tolerance) zero(a float64) bool {
+	return math.Abs(a) <= float64(t)
+}
+
+func (t Tolerance) eq(a, b float64) bool {
+	return a == b || math.Abs(a-b) <= float64(t)
+}
+
+func (t Tolerance) lt(a, b float64) bool {
+	return b-a > float64(t)
+}
+
+func (t Tolerance) le(a, b float64) bool {
+	return a-b <= float64(t)
+}
+
+func (t Tolerance) gt(a, b float64) bool {
+	return a-b > float64(t)
+}
+
+func (t Tolerance) ge(a, b float64) bool {
+	return b-a <= float64(t)
+}
+
+// equality which also holds between two NaNs
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func fpZero(a float64) bool {
+	return PostgresEpsilon.zero(a)
+}
+
+func fpEq(a, b float64) bool {
+	return PostgresEpsilon.eq(a, b)
+}
+
+func fpLt(a, b float64) bool {
+	return PostgresEpsilon.lt(a, b)
+}
+
+func fpLe(a, b float64) bool {
+	return PostgresEpsilon.le(a, b)
+}
+
+func fpGt(a, b float64) bool {
+	return PostgresEpsilon.gt(a, b)
+}
+
+func fpGe(a, b float64) bool {
+	return PostgresEpsilon.ge(a, b)
+}
+
+// slope of the line through two points, which is +Inf for a vertical line
+func slope(p1, p2 Point) float64 {
+	if fpEq(p1.x, p2.x) {
+		return math.Inf(1)
+	}
+	if fpEq(p1.y, p2.y) {
+		return 0
+	}
+	return (p1.y - p2.y) / (p1.x - p2.x)
+}
+
+// slope of a line perpendicular to the line through two points
+func invSlope(p1, p2 Point) float64 {
+	if fpEq(p1.x, p2.x) {
+		return 0
+	}
+	if fpEq(p1.y, p2.y) {
+		return math.Inf(1)
+	}
+	return (p1.x - p2.x) / (p2.y - p1.y)
+}
+
+// the smallest box enclosing all of the points
+func boundingBox(points []Point) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+
+	xmin, xmax := points[0].x, points[0].x
+	ymin, ymax := points[0].y, points[0].y
+
+	for _, pt := range points[1:] {
+		xmin = math.Min(xmin, pt.x)
+		xmax = math.Max(xmax, pt.x)
+		ymin = math.Min(ymin, pt.y)
+		ymax = math.Max(ymax, pt.y)
+	}
+
+	return NewBox(Point{x: xmin, y: ymin}, Point{x: xmax, y: ymax})
+}
+
+// ----------
+
+// Same returns whether two points are the same, within PostgresEpsilon. (~=)
+func (p Point) Same(other Point) bool {
+	return p.ApproxEqual(other, PostgresEpsilon)
+}
+
+// ApproxEqual returns whether both coordinates of two points are within
+// epsilon of each other.  NaN coordinates only equal each other.
+func (p Point) ApproxEqual(other Point, epsilon Tolerance) bool {
+	// if any NaNs are involved, insist on exact equality
+	if math.IsNaN(p.x) || math.IsNaN(p.y) || math.IsNaN(other.x) || math.IsNaN(other.y) {
+		return sameFloat(p.x, other.x) && sameFloat(p.y, other.y)
+	}
+
+	return epsilon.eq(p.x, other.x) && epsilon.eq(p.y, other.y)
+}
+
+// LeftOf returns whether this point is strictly left of another. (<<)
+func (p Point) LeftOf(other Point) bool {
+	return fpLt(p.x, other.x)
+}
+
+// RightOf returns whether this point is strictly right of another. (>>)
+func (p Point) RightOf(other Point) bool {
+	return fpGt(p.x, other.x)
+}
+
+// Below returns whether this point is strictly below another. (<<|)
+func (p Point) Below(other Point) bool {
+	return fpLt(p.y, other.y)
+}
+
+// Above returns whether this point is strictly above another. (|>>)
+func (p Point) Above(other Point) bool {
+	return fpGt(p.y, other.y)
+}
+
+// IsVertical returns whether this point and another are vertically
+// aligned. (?|)
+func (p Point) IsVertical(other Point) bool {
+	return fpEq(p.x, other.x)
+}
+
+// IsHorizontal returns whether this point and another are horizontally
+// aligned. (?-)
+func (p Point) IsHorizontal(other Point) bool {
+	return fpEq(p.y, other.y)
+}
+
+// ----------
+
+// Same returns whether two segments have the same endpoints, in the same
+// order. (=)
+func (s Segment) Same(other Segment) bool {
+	return s.ApproxEqual(other, PostgresEpsilon)
+}
+
+// ApproxEqual returns whether two segments have the same endpoints, in the
+// same order, within epsilon.
+func (s Segment) ApproxEqual(other Segment, epsilon Tolerance) bool {
+	return s[0].ApproxEqual(other[0], epsilon) && s[1].ApproxEqual(other[1], epsilon)
+}
+
+// Center returns the midpoint of the segment. (@@)
+func (s Segment) Center() Point {
+	return Point{x: (s[0].x + s[1].x) / 2, y: (s[0].y + s[1].y) / 2}
+}
+
+// Contains returns whether the point lies on the segment. (@>)
+func (s Segment) Contains(p Point) bool {
+	return fpEq(p.DistanceTo(s[0])+p.DistanceTo(s[1]), s[0].DistanceTo(s[1]))
+}
+
+// IsVertical returns whether the segment is vertical. (?|)
+func (s Segment) IsVertical() bool {
+	return fpEq(s[0].x, s[1].x)
+}
+
+// IsHorizontal returns whether the segment is horizontal. (?-)
+func (s Segment) IsHorizontal() bool {
+	return fpEq(s[0].y, s[1].y)
+}
+
+// IsParallel returns whether two segments are parallel. (?||)
+func (s Segment) IsParallel(other Segment) bool {
+	return fpEq(slope(s[0], s[1]), slope(other[0], other[1]))
+}
+
+// IsPerpendicular returns whether two segments are perpendicular. (?-|)
+func (s Segment) IsPerpendicular(other Segment) bool {
+	return fpEq(slope(s[0], s[1]), invSlope(other[0], other[1]))
+}
+
+// Intersection returns the point where two segments cross. (#)
+// Like postgres, parallel segments are never considered to cross, even
+// where they overlap.
+func (s Segment) Intersection(other Segment) (Point, bool) {
+	p, ok := other.AsLine().IntersectSegment(s)
+
+	if !ok || !other.Contains(p) {
+		return Point{}, false
+	}
+
+	return p, true
+}
+
+// Intersects returns whether two segments cross. (?#)
+func (s Segment) Intersects(other Segment) bool {
+	_, ok := s.Intersection(other)
+	return ok
+}
+
+// IntersectsBox returns whether the segment crosses or lies within a
+// box. (?#)
+func (s Segment) IntersectsBox(b Box) bool {
+
+	// nothing close to overlap? then not going to intersect
+	if !s.AsBox().Overlaps(b) {
+		return false
+	}
+
+	// an endpoint of segment is inside box? then clearly intersects
+	if b.Contains(s[0]) || b.Contains(s[1]) {
+		return true
+	}
+
+	for _, edge := range b.edges() {
+		if edge.Intersects(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClosestPoint returns the point on the segment closest to p. (##)
+func (s Segment) ClosestPoint(p Point) Point {
+	c, _ := s.closest(p)
+	return c
+}
+
+// DistanceTo returns the distance from the segment to a point. (<->)
+func (s Segment) DistanceTo(p Point) float64 {
+	_, d := s.closest(p)
+	return d
+}
+
+// DistanceToSegment returns the distance between two segments. (<->)
+func (s Segment) DistanceToSegment(other Segment) float64 {
+	if s.Intersects(other) {
+		return 0
+	}
+
+	d := s.DistanceTo(other[0])
+	d = math.Min(d, s.DistanceTo(other[1]))
+	d = math.Min(d, other.DistanceTo(s[0]))
+	d = math.Min(d, other.DistanceTo(s[1]))
+	return d
+}
+
+// closest point to p on the segment, and its distance, found by dropping
+// a perpendicular to the segment
+func (s Segment) closest(p Point) (Point, float64) {
+	c := s.closestToLine(lineThrough(p, invSlope(s[0], s[1])))
+	return c, c.DistanceTo(p)
+}
+
+// closest point on the segment to a line
+func (s Segment) closestToLine(l Line) Point {
+	if p, ok := l.IntersectSegment(s); ok {
+		return p
+	}
+
+	_, d1 := l.closest(s[0])
+	_, d2 := l.closest(s[1])
+
+	if d1 < d2 {
+		return s[0]
+	}
+	return s[1]
+}
+
+// ----------
+
+// Same returns whether two lines are the same, within PostgresEpsilon.
+// Lines are the same when their coefficients are proportional. (=)
+func (l Line) Same(other Line) bool {
+	return l.ApproxEqual(other, PostgresEpsilon)
+}
+
+// ApproxEqual returns whether two lines are the same, comparing their
+// coefficients within epsilon once they are scaled alike.
+func (l Line) ApproxEqual(other Line, epsilon Tolerance) bool {
+	// if any NaNs are involved, insist on exact equality
+	if math.IsNaN(l.a) || math.IsNaN(l.b) || math.IsNaN(l.c) ||
+		math.IsNaN(other.a) || math.IsNaN(other.b) || math.IsNaN(other.c) {
+		return sameFloat(l.a, other.a) && sameFloat(l.b, other.b) && sameFloat(l.c, other.c)
+	}
+
+	// otherwise, lines whose coefficients are proportional are the same
+	ratio := 1.0
+	switch {
+	case !epsilon.zero(other.a):
+		ratio = l.a / other.a
+	case !epsilon.zero(other.b):
+		ratio = l.b / other.b
+	case !epsilon.zero(other.c):
+		ratio = l.c / other.c
+	}
+
+	return epsilon.eq(l.a, ratio*other.a) && epsilon.eq(l.b, ratio*other.b) && epsilon.eq(l.c, ratio*other.c)
+}
+
+// Contains returns whether the point lies on the line. (@>)
+func (l Line) Contains(p Point) bool {
+	return fpZero(l.a*p.x + l.b*p.y + l.c)
+}
+
+// Intersects returns whether two lines cross. (?#)
+func (l Line) Intersects(other Line) bool {
+	_, ok := l.Intersection(other)
+	return ok
+}
+
+// IsVertical returns whether the line is vertical. (?|)
+func (l Line) IsVertical() bool {
+	return fpZero(l.b)
+}
+
+// IsHorizontal returns whether the line is horizontal. (?-)
+func (l Line) IsHorizontal() bool {
+	return fpZero(l.a)
+}
+
+// ClosestPoint returns the point on the line closest to p. (##)
+func (l Line) ClosestPoint(p Point) Point {
+	c, _ := l.closest(p)
+	return c
+}
+
+// slope of a line perpendicular to this one
+func (l Line) invSlope() float64 {
+	if fpZero(l.a) {
+		return math.Inf(1)
+	}
+	if fpZero(l.b) {
+		return 0
+	}
+	return l.b / l.a
+}
+
+// closest point to p on the line, and its distance, found by dropping a
+// perpendicular to the line
+func (l Line) closest(p Point) (Point, float64) {
+	c, ok := lineThrough(p, l.invSlope()).Intersection(l)
+
+	// only possible with NaN coordinates
+	if !ok {
+		return p, math.NaN()
+	}
+
+	return c, c.DistanceTo(p)
+}
+
+// ----------
+
+// Same returns whether two boxes have the same corners. (~=)
+func (b Box) Same(other Box) bool {
+	return b.ApproxEqual(other, PostgresEpsilon)
+}
+
+// ApproxEqual returns whether two boxes have the same corners, within
+// epsilon.
+func (b Box) ApproxEqual(other Box, epsilon Tolerance) bool {
+	return b[0].ApproxEqual(other[0], epsilon) && b[1].ApproxEqual(other[1], epsilon)
+}
+
+// Center returns the center point of the box. (@@)
+func (b Box) Center() Point {
+	return Point{x: (b[0].x + b[1].x) / 2, y: (b[0].y + b[1].y) / 2}
+}
+
+// Overlaps returns whether two boxes overlap, including touching at their
+// edges. (&&)
+func (b Box) Overlaps(other Box) bool {
+	return fpLe(b[1].x, other[0].x) && fpLe(other[1].x, b[0].x) &&
+		fpLe(b[1].y, other[0].y) && fpLe(other[1].y, b[0].y)
+}
+
+// ContainsBox returns whether another box is on or inside this one. (@>)
+func (b Box) ContainsBox(other Box) bool {
+	return fpGe(b[0].x, other[0].x) && fpLe(b[1].x, other[1].x) &&
+		fpGe(b[0].y, other[0].y) && fpLe(b[1].y, other[1].y)
+}
+
+// ContainedBy returns whether this box is on or inside another. (<@)
+func (b Box) ContainedBy(other Box) bool {
+	return other.ContainsBox(b)
+}
+
+// LeftOf returns whether this box is strictly left of another. (<<)
+func (b Box) LeftOf(other Box) bool {
+	return fpLt(b[0].x, other[1].x)
+}
+
+// RightOf returns whether this box is strictly right of another. (>>)
+func (b Box) RightOf(other Box) bool {
+	return fpGt(b[1].x, other[0].x)
+}
+
+// Below returns whether this box is strictly below another. (<<|)
+func (b Box) Below(other Box) bool {
+	return fpLt(b[0].y, other[1].y)
+}
+
+// Above returns whether this box is strictly above another. (|>>)
+func (b Box) Above(other Box) bool {
+	return fpGt(b[1].y, other[0].y)
+}
+
+// OverLeft returns whether this box does not extend to the right of
+// another. (&<)
+func (b Box) OverLeft(other Box) bool {
+	return fpLe(b[0].x, other[0].x)
+}
+
+// OverRight returns whether this box does not extend to the left of
+// another. (&>)
+func (b Box) OverRight(other Box) bool {
+	return fpGe(b[1].x, other[1].x)
+}
+
+// OverBelow returns whether this box does not extend above another. (&<|)
+func (b Box) OverBelow(other Box) bool {
+	return fpLe(b[0].y, other[0].y)
+}
+
+// OverAbove returns whether this box does not extend below another. (|&>)
+func (b Box) OverAbove(other Box) bool {
+	return fpGe(b[1].y, other[1].y)
+}
+
+// Intersection returns the box where two boxes overlap, if they do. (#)
+func (b Box) Intersection(other Box) (Box, bool) {
+	if !b.Overlaps(other) {
+		return Box{}, false
+	}
+
+	return Box{
+		Point{x: math.Min(b[0].x, other[0].x), y: math.Min(b[0].y, other[0].y)},
+		Point{x: math.Max(b[1].x, other[1].x), y: math.Max(b[1].y, other[1].y)},
+	}, true
+}
+
+// ClosestPoint returns the point on or in the box closest to p. (##)
+func (b Box) ClosestPoint(p Point) Point {
+	c, _ := b.closest(p)
+	return c
+}
+
+// DistanceTo returns the distance from the box to a point, which is zero
+// for points inside the box. (<->)
+func (b Box) DistanceTo(p Point) float64 {
+	_, d := b.closest(p)
+	return d
+}
+
+// DistanceToBox returns the distance between the centers of two
+// boxes. (<->)
+func (b Box) DistanceToBox(other Box) float64 {
+	return b.Center().DistanceTo(other.Center())
+}
+
+// the four sides of the box
+func (b Box) edges() [4]Segment {
+	ul := Point{x: b[1].x, y: b[0].y}
+	lr := Point{x: b[0].x, y: b[1].y}
+
+	return [4]Segment{
+		NewSegment(b[1], ul),
+		NewSegment(b[0], ul),
+		NewSegment(b[1], lr),
+		NewSegment(b[0], lr),
+	}
+}
+
+// closest point to p in the box, and its distance
+func (b Box) closest(p Point) (Point, float64) {
+	if b.Contains(p) {
+		return p, 0
+	}
+
+	edges := b.edges()
+	c, d := edges[0].closest(p)
+
+	for _, e := range edges[1:] {
+		if ec, ed := e.closest(p); ed < d {
+			c, d = ec, ed
+		}
+	}
+
+	return c, d
+}
+
+// ----------
+
+// Same returns whether two circles have the same center and radius. (~=)
+func (c Circle) Same(other Circle) bool {
+	return c.ApproxEqual(other, PostgresEpsilon)
+}
+
+// ApproxEqual returns whether two circles have the same center and radius,
+// within epsilon.
+func (c Circle) ApproxEqual(other Circle, epsilon Tolerance) bool {
+	sameRadius := (math.IsNaN(c.radius) && math.IsNaN(other.radius)) || epsilon.eq(c.radius, other.radius)
+	return sameRadius && c.center.ApproxEqual(other.center, epsilon)
+}
+
+// Center returns the center point of the circle. (@@)
+func (c Circle) Center() Point {
+	return c.center
+}
+
+// Overlaps returns whether two circles overlap, including touching. (&&)
+func (c Circle) Overlaps(other Circle) bool {
+	return fpLe(c.center.DistanceTo(other.center), c.radius+other.radius)
+}
+
+// ContainsCircle returns whether another circle is on or inside this
+// one. (@>)
+func (c Circle) ContainsCircle(other Circle) bool {
+	return fpLe(c.center.DistanceTo(other.center)+other.radius, c.radius)
+}
+
+// ContainedBy returns whether this circle is on or inside another. (<@)
+func (c Circle) ContainedBy(other Circle) bool {
+	return other.ContainsCircle(c)
+}
+
+// LeftOf returns whether this circle is strictly left of another. (<<)
+func (c Circle) LeftOf(other Circle) bool {
+	return fpLt(c.center.x+c.radius, other.center.x-other.radius)
+}
+
+// RightOf returns whether this circle is strictly right of another. (>>)
+func (c Circle) RightOf(other Circle) bool {
+	return fpGt(c.center.x-c.radius, other.center.x+other.radius)
+}
+
+// Below returns whether this circle is strictly below another. (<<|)
+func (c Circle) Below(other Circle) bool {
+	return fpLt(c.center.y+c.radius, other.center.y-other.radius)
+}
+
+// Above returns whether this circle is strictly above another. (|>>)
+func (c Circle) Above(other Circle) bool {
+	return fpGt(c.center.y-c.radius, other.center.y+other.radius)
+}
+
+// OverLeft returns whether this circle does not extend to the right of
+// another. (&<)
+func (c Circle) OverLeft(other Circle) bool {
+	return fpLe(c.center.x+c.radius, other.center.x+other.radius)
+}
+
+// OverRight returns whether this circle does not extend to the left of
+// another. (&>)
+func (c Circle) OverRight(other Circle) bool {
+	return fpGe(c.center.x-c.radius, other.center.x-other.radius)
+}
+
+// OverBelow returns whether this circle does not extend above
+// another. (&<|)
+func (c Circle) OverBelow(other Circle) bool {
+	return fpLe(c.center.y+c.radius, other.center.y+other.radius)
+}
+
+// OverAbove returns whether this circle does not extend below
+// another. (|&>)
+func (c Circle) OverAbove(other Circle) bool {
+	return fpGe(c.center.y-c.radius, other.center.y-other.radius)
+}
+
+// DistanceTo returns the distance from the circle to a point, which is zero
+// for points inside the circle. (<->)
+func (c Circle) DistanceTo(p Point) float64 {
+	return math.Max(p.DistanceTo(c.center)-c.radius, 0)
+}
+
+// DistanceToCircle returns the distance between the edges of two circles,
+// which is zero if they overlap. (<->)
+func (c Circle) DistanceToCircle(other Circle) float64 {
+	return math.Max(c.center.DistanceTo(other.center)-(c.radius+other.radius), 0)
+}
+
+// ----------
+
+// ApproxEqual returns whether two paths are both open or both closed, and
+// have the same points in the same order, within epsilon.
+func (p Path) ApproxEqual(other Path, epsilon Tolerance) bool {
+	return p.closed == other.closed && pointsApproxEqual(p.point, other.point, epsilon)
+}
+
+// Contains returns whether the point lies on an open path, or on or inside
+// a closed one. (@>)
+func (p Path) Contains(pt Point) bool {
+	if len(p.point) == 0 {
+		return false
+	}
+
+	if p.closed {
+		return pointInside(pt, p.point) != 0
+	}
+
+	for _, s := range p.Segments() {
+		if s.Contains(pt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// the segments of the path, as postgres iterates them; unlike Segments(),
+// a closed path of one point has a single, degenerate segment
+func (p Path) edges() []Segment {
+	if p.closed && len(p.point) == 1 {
+		return []Segment{NewSegment(p.point[0], p.point[0])}
+	}
+	return p.Segments()
+}
+
+// DistanceTo returns the distance from the closest segment of the path to a
+// point. (<->)
+func (p Path) DistanceTo(pt Point) float64 {
+	segments := p.edges()
+
+	if len(segments) == 0 {
+		return 0
+	}
+
+	d := segments[0].DistanceTo(pt)
+	for _, s := range segments[1:] {
+		d = math.Min(d, s.DistanceTo(pt))
+	}
+
+	return d
+}
+
+// DistanceToPath returns the distance between the closest segments of two
+// paths.  The bool is false if either path has no segments. (<->)
+func (p Path) DistanceToPath(other Path) (float64, bool) {
+	d := math.Inf(1)
+	found := false
+
+	for _, s1 := range p.edges() {
+		for _, s2 := range other.edges() {
+			d = math.Min(d, s1.DistanceToSegment(s2))
+			found = true
+		}
+	}
+
+	return d, found
+}
+
+// Intersects returns whether any segments of two paths cross. (?#)
+func (p Path) Intersects(other Path) bool {
+	if len(p.point) == 0 || len(other.point) == 0 {
+		return false
+	}
+
+	if !boundingBox(p.point).Overlaps(boundingBox(other.point)) {
+		return false
+	}
+
+	for _, s1 := range p.edges() {
+		for _, s2 := range other.edges() {
+			if s1.Intersects(s2) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ----------
+
+// Same returns whether two polygons have the same vertices, starting
+// anywhere and in either direction. (~=)
+func (p Polygon) Same(other Polygon) bool {
+	n := len(p.point)
+
+	if n != len(other.point) {
+		return false
+	}
+
+	// find match for first point
+	for i := range other.point {
+		if !other.point[i].Same(p.point[0]) {
+			continue
+		}
+
+		// match found? then look forward through remaining points
+		ii := 1
+		for j := i + 1; ii < n; ii, j = ii+1, j+1 {
+			if !other.point[j%n].Same(p.point[ii]) {
+				break
+			}
+		}
+		if ii == n {
+			return true
+		}
+
+		// match not found forwards? then look backwards
+		ii = 1
+		for j := i - 1; ii < n; ii, j = ii+1, j-1 {
+			if !other.point[(j+n)%n].Same(p.point[ii]) {
+				break
+			}
+		}
+		if ii == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApproxEqual returns whether two polygons have the same vertices, within
+// epsilon.  Unlike Same, the vertices must be in the same order, starting
+// from the same one.
+func (p Polygon) ApproxEqual(other Polygon, epsilon Tolerance) bool {
+	return pointsApproxEqual(p.point, other.point, epsilon)
+}
+
+func pointsApproxEqual(p1, p2 []Point, epsilon Tolerance) bool {
+	if len(p1) != len(p2) {
+		return false
+	}
+
+	for i := range p1 {
+		if !p1[i].ApproxEqual(p2[i], epsilon) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Center returns the average of the polygon's vertices. (@@)
+func (p Polygon) Center() Point {
+	var x, y float64
+
+	for _, pt := range p.point {
+		x += pt.x
+		y += pt.y
+	}
+
+	n := float64(len(p.point))
+	return Point{x: x / n, y: y / n}
+}
+
+// Overlaps returns whether two polygons overlap, including touching. (&&)
+func (p Polygon) Overlaps(other Polygon) bool {
+	if len(p.point) == 0 || len(other.point) == 0 {
+		return false
+	}
+
+	if !p.Box().Overlaps(other.Box()) {
+		return false
+	}
+
+	// any crossed edges?
+	for _, s1 := range p.Path().edges() {
+		for _, s2 := range other.Path().edges() {
+			if s1.Intersects(s2) {
+				return true
+			}
+		}
+	}
+
+	// otherwise, one must be entirely inside the other to overlap
+	return pointInside(p.point[0], other.point) != 0 || pointInside(other.point[0], p.point) != 0
+}
+
+// ContainsPolygon returns whether another polygon is on or inside this
+// one. (@>)
+func (p Polygon) ContainsPolygon(other Polygon) bool {
+	if len(p.point) == 0 || len(other.point) == 0 {
+		return false
+	}
+
+	if !p.Box().ContainsBox(other.Box()) {
+		return false
+	}
+
+	n := len(other.point)
+	prev := other.point[n-1]
+
+	for _, pt := range other.point {
+		if !segmentInsidePolygon(prev, pt, p.point, 0) {
+			return false
+		}
+		prev = pt
+	}
+
+	return true
+}
+
+// ContainedBy returns whether this polygon is on or inside another. (<@)
+func (p Polygon) ContainedBy(other Polygon) bool {
+	return other.ContainsPolygon(p)
+}
+
+// LeftOf returns whether this polygon is strictly left of another. (<<)
+func (p Polygon) LeftOf(other Polygon) bool {
+	return p.Box()[0].x < other.Box()[1].x
+}
+
+// RightOf returns whether this polygon is strictly right of another. (>>)
+func (p Polygon) RightOf(other Polygon) bool {
+	return p.Box()[1].x > other.Box()[0].x
+}
+
+// Below returns whether this polygon is strictly below another. (<<|)
+func (p Polygon) Below(other Polygon) bool {
+	return p.Box()[0].y < other.Box()[1].y
+}
+
+// Above returns whether this polygon is strictly above another. (|>>)
+func (p Polygon) Above(other Polygon) bool {
+	return p.Box()[1].y > other.Box()[0].y
+}
+
+// OverLeft returns whether this polygon does not extend to the right of
+// another. (&<)
+func (p Polygon) OverLeft(other Polygon) bool {
+	return p.Box()[0].x <= other.Box()[0].x
+}
+
+// OverRight returns whether this polygon does not extend to the left of
+// another. (&>)
+func (p Polygon) OverRight(other Polygon) bool {
+	return p.Box()[1].x >= other.Box()[1].x
+}
+
+// OverBelow returns whether this polygon does not extend above
+// another. (&<|)
+func (p Polygon) OverBelow(other Polygon) bool {
+	return p.Box()[0].y <= other.Box()[0].y
+}
+
+// OverAbove returns whether this polygon does not extend below
+// another. (|&>)
+func (p Polygon) OverAbove(other Polygon) bool {
+	return p.Box()[1].y >= other.Box()[1].y
+}
+
+// DistanceTo returns the distance from the polygon to a point, which is
+// zero for points inside the polygon. (<->)
+func (p Polygon) DistanceTo(pt Point) float64 {
+	if len(p.point) == 0 || pointInside(pt, p.point) != 0 {
+		return 0
+	}
+
+	return p.Path().DistanceTo(pt)
+}
+
+// Determines whether the segment from a to b lies inside the polygon with
+// the given vertices, checking its edges from start onwards.  A port of
+// postgres' lseg_inside_poly.
+func segmentInsidePolygon(a, b Point, poly []Point, start int) bool {
+	t := NewSegment(a, b)
+	res := true
+	intersection := false
+
+	prev := len(poly) - 1
+	if start > 0 {
+		prev = start - 1
+	}
+	s := Segment{poly[prev], Point{}}
+
+	for i := start; i < len(poly) && res; i++ {
+		s[1] = poly[i]
+
+		if s.Contains(t[0]) {
+			if s.Contains(t[1]) {
+				// t is contained by s
+				return true
+			}
+
+			// Y-cross
+			res = touchedSegmentInsidePolygon(t[0], t[1], s, poly, i+1)
+		} else if s.Contains(t[1]) {
+			// Y-cross
+			res = touchedSegmentInsidePolygon(t[1], t[0], s, poly, i+1)
+		} else if interpt, ok := t.Intersection(s); ok {
+			// segments are X-crossing, go to check each subsegment
+			intersection = true
+			res = segmentInsidePolygon(t[0], interpt, poly, i+1)
+			if res {
+				res = segmentInsidePolygon(t[1], interpt, poly, i+1)
+			}
+		}
+
+		s[0] = s[1]
+	}
+
+	if res && !intersection {
+		// if X-intersection wasn't found, then check central point of the
+		// tested segment.  Otherwise we already checked all subsegments.
+		res = pointInside(t.Center(), poly) != 0
+	}
+
+	return res
+}
+
+// Determines whether the segment from a to b lies inside the polygon, given
+// that a lies on the polygon's edge s and b does not.  A port of postgres'
+// touched_lseg_inside_poly.
+func touchedSegmentInsidePolygon(a, b Point, s Segment, poly []Point, start int) bool {
+	t := NewSegment(a, b)
+
+	switch {
+	case a.Same(s[0]):
+		if t.Contains(s[1]) {
+			return segmentInsidePolygon(b, s[1], poly, start)
+		}
+	case a.Same(s[1]):
+		if t.Contains(s[0]) {
+			return segmentInsidePolygon(b, s[0], poly, start)
+		}
+	case t.Contains(s[0]):
+		return segmentInsidePolygon(b, s[0], poly, start)
+	case t.Contains(s[1]):
+		return segmentInsidePolygon(b, s[1], poly, start)
+	}
+
+	// primary segment is not crossed
+	return true
+}
+
+// ----------
+
+// Determines whether a point is inside the polygon with the given vertices,
+// by counting how many times its edges cross the positive X axis, once the
+// point is moved to the origin.  Returns 0 if outside, 1 if inside, and 2 if
+// on the boundary.  A port of postgres' point_inside.
+func pointInside(pt Point, poly []Point) int {
+	x0 := poly[0].x - pt.x
+	y0 := poly[0].y - pt.y
+
+	prevX, prevY := x0, y0
+	total := 0
+
+	for _, v := range poly[1:] {
+		x := v.x - pt.x
+		y := v.y - pt.y
+
+		cross := crossing(x, y, prevX, prevY)
+		if cross == onPolygon {
+			return 2
+		}
+		total += cross
+
+		prevX, prevY = x, y
+	}
+
+	// and the edge back to the first point
+	cross := crossing(x0, y0, prevX, prevY)
+	if cross == onPolygon {
+		return 2
+	}
+	total += cross
+
+	if total != 0 {
+		return 1
+	}
+	return 0
+}
+
+// returned by crossing() when the edge passes through the origin
+const onPolygon = math.MaxInt32
+
+// crossing determines how the edge from (prevX,prevY) to (x,y) crosses the
+// positive X axis: 0 for no crossing, +/-2 for a crossing (+/-1 for a half
+// crossing), in the direction of y.  A port of postgres' lseg_crossing.
+func crossing(x, y, prevX, prevY float64) int {
+
+	if fpZero(y) {
+		// on X axis
+		if fpZero(x) {
+			return onPolygon
+		}
+
+		if fpGt(x, 0) {
+			if fpZero(prevY) {
+				// both points on the X axis
+				if fpGt(prevX, 0) {
+					return 0
+				}
+				return onPolygon
+			}
+			if fpLt(prevY, 0) {
+				return 1
+			}
+			return -1
+		}
+
+		// x < 0, not on the positive X axis
+		if fpZero(prevY) {
+			if fpLt(prevX, 0) {
+				return 0
+			}
+			return onPolygon
+		}
+		return 0
+	}
+
+	// compute y crossing direction from previous point
+	ySign := -1
+	if fpGt(y, 0) {
+		ySign = 1
+	}
+
+	if fpZero(prevY) {
+		// previous point was on the X axis
+		if fpLt(prevX, 0) {
+			return 0
+		}
+		return ySign
+	}
+
+	if (ySign < 0 && fpLt(prevY, 0)) || (ySign > 0 && fpGt(prevY, 0)) {
+		// both above or below the X axis
+		return 0
+	}
+
+	// y and prevY cross the X axis
+	if fpGe(x, 0) && fpGt(prevX, 0) {
+		// both non-negative, so cross the positive X axis
+		return 2 * ySign
+	}
+
+	if fpLt(x, 0) && fpLe(prevX, 0) {
+		// both non-positive, so do not cross the positive X axis
+		return 0
+	}
+
+	// x and y cross axes; which side of the origin does the edge pass?
+	z := (x-prevX)*y - (y-prevY)*x
+	if fpZero(z) {
+		return onPolygon
+	}
+
+	if (ySign < 0 && fpLt(z, 0)) || (ySign > 0 && fpGt(z, 0)) {
+		return 0
+	}
+
+	return 2 * ySign
+}
@@ -0,0 +1,446 @@
+package geometry
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+// Points and boxes from the tables in postgres' point and box regression
+// tests (src/test/regress/sql/point.sql and box.sql), and the rows the
+// server returns for their queries.
+
+var regressPoints = []Point{
+	{0, 0},
+	{-10, 0},
+	{-3, 4},
+	{5.1, 34.5},
+	{-5, -12},
+	{1e-300, -1e-300},
+	{10, 10},
+}
+
+var regressBoxes = []Box{
+	NewBox(Point{2, 2}, Point{0, 0}),
+	NewBox(Point{1, 1}, Point{3, 3}),
+	NewBox(Point{-8, 2}, Point{-2, -10}),
+	NewBox(Point{2.5, 2.5}, Point{2.5, 3.5}),
+	NewBox(Point{3, 3}, Point{3, 3}),
+}
+
+func selectPoints(f func(Point) bool) []Point {
+	r := []Point{}
+	for _, p := range regressPoints {
+		if f(p) {
+			r = append(r, p)
+		}
+	}
+	return r
+}
+
+func selectBoxes(f func(Box) bool) []Box {
+	r := []Box{}
+	for _, b := range regressBoxes {
+		if f(b) {
+			r = append(r, b)
+		}
+	}
+	return r
+}
+
+func TestPointOperators(t *testing.T) {
+
+	Convey("Given the point regression table", t, func() {
+
+		Convey("Positional operators should select the same rows as postgres", func() {
+			So(selectPoints(func(p Point) bool { return p.LeftOf(Origin) }), ShouldResemble,
+				[]Point{{-10, 0}, {-3, 4}, {-5, -12}})
+			So(selectPoints(func(p Point) bool { return Origin.RightOf(p) }), ShouldResemble,
+				[]Point{{-10, 0}, {-3, 4}, {-5, -12}})
+			So(selectPoints(func(p Point) bool { return p.Below(Origin) }), ShouldResemble,
+				[]Point{{-5, -12}})
+			So(selectPoints(func(p Point) bool { return p.Above(Origin) }), ShouldResemble,
+				[]Point{{-3, 4}, {5.1, 34.5}, {10, 10}})
+			So(selectPoints(func(p Point) bool { return p.IsVertical(Origin) }), ShouldResemble,
+				[]Point{{0, 0}, {1e-300, -1e-300}})
+			So(selectPoints(func(p Point) bool { return p.IsHorizontal(Origin) }), ShouldResemble,
+				[]Point{{0, 0}, {-10, 0}, {1e-300, -1e-300}})
+			So(selectPoints(func(p Point) bool { return p.Same(Point{5.1, 34.5}) }), ShouldResemble,
+				[]Point{{5.1, 34.5}})
+		})
+
+		Convey("Containment should select the same rows as postgres", func() {
+			box := NewBox(Point{0, 0}, Point{100, 100})
+			So(selectPoints(box.Contains), ShouldResemble,
+				[]Point{{0, 0}, {5.1, 34.5}, {10, 10}})
+
+			path := NewPath(Point{0, 0}, Point{-10, 0}, Point{-10, 10})
+			So(selectPoints(path.Contains), ShouldResemble,
+				[]Point{{0, 0}, {-10, 0}, {1e-300, -1e-300}})
+
+			poly := NewPolygon(Point{0, 0}, Point{-10, 0}, Point{-10, 10})
+			So(selectPoints(poly.Contains), ShouldResemble,
+				[]Point{{0, 0}, {-10, 0}, {1e-300, -1e-300}})
+		})
+
+		Convey("NaN points should only be the same as each other", func() {
+			nan := Point{math.NaN(), math.NaN()}
+			So(nan.Same(nan), ShouldBeTrue)
+			So(nan.Same(Origin), ShouldBeFalse)
+
+			mixed := Point{math.NaN(), 1}
+			So(mixed.Same(Point{math.NaN(), 1}), ShouldBeTrue)
+			So(mixed.Same(Point{math.NaN(), 1 + 1e-12}), ShouldBeFalse)
+			So(mixed.Same(nan), ShouldBeFalse)
+			So(NewCircle(mixed, 2).Same(NewCircle(Point{math.NaN(), 1}, 2)), ShouldBeTrue)
+		})
+	})
+}
+
+func TestBoxOperators(t *testing.T) {
+
+	Convey("Given the box regression table", t, func() {
+
+		Convey("Overlap and position should select the same rows as postgres", func() {
+			So(selectBoxes(func(b Box) bool { return b.Overlaps(NewBox(Point{2.5, 2.5}, Point{1, 1})) }), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[1], regressBoxes[3]})
+			So(selectBoxes(func(b Box) bool { return b.OverLeft(NewBox(Point{2, 2}, Point{2.5, 2.5})) }), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[2], regressBoxes[3]})
+			So(selectBoxes(func(b Box) bool { return b.OverRight(NewBox(Point{2, 2}, Point{2.5, 2.5})) }), ShouldResemble,
+				[]Box{regressBoxes[3], regressBoxes[4]})
+			So(selectBoxes(func(b Box) bool { return b.LeftOf(NewBox(Point{3, 3}, Point{5, 5})) }), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[2], regressBoxes[3]})
+			So(selectBoxes(func(b Box) bool { return NewBox(Point{3, 3}, Point{5, 5}).RightOf(b) }), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[2], regressBoxes[3]})
+			So(selectBoxes(func(b Box) bool { return b.Below(NewBox(Point{0, 3}, Point{5, 5})) }), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[2]})
+			So(selectBoxes(func(b Box) bool { return b.Above(NewBox(Point{0, 0}, Point{5, 2.5})) }), ShouldResemble,
+				[]Box{regressBoxes[4]})
+		})
+
+		Convey("Containment should select the same rows as postgres", func() {
+			outer := NewBox(Point{0, 0}, Point{3, 3})
+			So(selectBoxes(func(b Box) bool { return b.ContainedBy(outer) }), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[1], regressBoxes[4]})
+			So(selectBoxes(outer.ContainsBox), ShouldResemble,
+				[]Box{regressBoxes[0], regressBoxes[1], regressBoxes[4]})
+
+			// wholly-contained
+			for i, b1 := range regressBoxes {
+				for j, b2 := range regressBoxes {
+					wholly := b1.ContainsBox(b2) && !b1.Same(b2)
+					So(wholly, ShouldEqual, i == 1 && j == 4)
+				}
+			}
+		})
+
+		Convey("Centers should match postgres", func() {
+			centers := []Point{}
+			for _, b := range regressBoxes {
+				centers = append(centers, b.Center())
+			}
+			So(centers, ShouldResemble, []Point{{1, 1}, {2, 2}, {-5, -4}, {2.5, 3}, {3, 3}})
+		})
+
+		Convey("Intersections should be the overlapping area", func() {
+			b, ok := regressBoxes[0].Intersection(regressBoxes[1])
+			So(ok, ShouldBeTrue)
+			So(b, ShouldResemble, NewBox(Point{1, 1}, Point{2, 2}))
+
+			_, ok = regressBoxes[0].Intersection(regressBoxes[2])
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Distances and closest points should be to the nearest edge", func() {
+			b := regressBoxes[0]
+			So(b.ClosestPoint(Point{3, 1}), ShouldResemble, Point{2, 1})
+			So(b.DistanceTo(Point{3, 1}), ShouldEqual, float64(1))
+			So(b.ClosestPoint(Point{3, 3}), ShouldResemble, Point{2, 2})
+			So(b.DistanceTo(Point{3, 3}), ShouldAlmostEqual, math.Sqrt(2))
+			So(b.ClosestPoint(Point{1, 1.5}), ShouldResemble, Point{1, 1.5})
+			So(b.DistanceTo(Point{1, 1.5}), ShouldEqual, float64(0))
+
+			So(b.DistanceToBox(regressBoxes[1]), ShouldAlmostEqual, math.Sqrt(2))
+		})
+	})
+}
+
+func TestSegmentOperators(t *testing.T) {
+
+	Convey("Given some segments", t, func() {
+		s1 := NewSegment(Point{0, 0}, Point{2, 2})
+		s2 := NewSegment(Point{0, 2}, Point{2, 0})
+		s3 := NewSegment(Point{3, 3}, Point{4, 4})
+		vert := NewSegment(Point{1, 0}, Point{1, 5})
+		horiz := NewSegment(Point{-1, 5}, Point{7, 5})
+
+		Convey("Crossing segments should intersect", func() {
+			p, ok := s1.Intersection(s2)
+			So(ok, ShouldBeTrue)
+			So(p, ShouldResemble, Point{1, 1})
+			So(s1.Intersects(vert), ShouldBeTrue)
+			So(vert.Intersects(horiz), ShouldBeTrue)
+			So(s2.Intersects(horiz), ShouldBeFalse)
+		})
+
+		Convey("Like postgres, collinear segments should never intersect", func() {
+			So(s1.Intersects(s3), ShouldBeFalse)
+			So(s1.Intersects(NewSegment(Point{1, 1}, Point{3, 3})), ShouldBeFalse)
+		})
+
+		Convey("Orientation should be recognized", func() {
+			So(s1.IsParallel(s3), ShouldBeTrue)
+			So(s1.IsPerpendicular(s2), ShouldBeTrue)
+			So(s1.IsPerpendicular(s3), ShouldBeFalse)
+			So(vert.IsVertical(), ShouldBeTrue)
+			So(vert.IsPerpendicular(horiz), ShouldBeTrue)
+			So(horiz.IsHorizontal(), ShouldBeTrue)
+			So(s1.IsVertical() || s1.IsHorizontal(), ShouldBeFalse)
+		})
+
+		Convey("Containment should allow for rounding", func() {
+			So(s1.Contains(Point{1, 1}), ShouldBeTrue)
+			So(s1.Contains(Point{1, 1 + 1e-13}), ShouldBeTrue)
+			So(s1.Contains(Point{3, 3}), ShouldBeFalse)
+		})
+
+		Convey("Closest points and distances should match postgres", func() {
+			So(s1.Center(), ShouldResemble, Point{1, 1})
+			So(s1.ClosestPoint(Point{2, 0}), ShouldResemble, Point{1, 1})
+			So(s1.DistanceTo(Point{2, 0}), ShouldAlmostEqual, math.Sqrt(2))
+			So(s1.ClosestPoint(Point{5, 5}), ShouldResemble, Point{2, 2})
+			So(s1.DistanceTo(Point{5, 5}), ShouldAlmostEqual, math.Sqrt(18))
+			So(s1.DistanceToSegment(s2), ShouldEqual, float64(0))
+			So(s1.DistanceToSegment(s3), ShouldAlmostEqual, math.Sqrt(2))
+		})
+
+		Convey("Segments should intersect boxes they cross or lie within", func() {
+			b := NewBox(Point{0, 0}, Point{2, 2})
+			So(NewSegment(Point{-1, 1}, Point{3, 1}).IntersectsBox(b), ShouldBeTrue)
+			So(NewSegment(Point{0.5, 0.5}, Point{1, 1}).IntersectsBox(b), ShouldBeTrue)
+			So(NewSegment(Point{-1, 2}, Point{2, -1}).IntersectsBox(b), ShouldBeTrue)
+			So(NewSegment(Point{-1, 3}, Point{3, 3}).IntersectsBox(b), ShouldBeFalse)
+			So(NewSegment(Point{-2, 1}, Point{1, 4}).IntersectsBox(b), ShouldBeFalse)
+		})
+	})
+}
+
+func TestLineOperators(t *testing.T) {
+
+	Convey("Given some lines", t, func() {
+		diag := NewLine(Origin, Point{1, 1})
+		vert := NewLine(Point{2, 0}, Point{2, 5})
+
+		So(diag.Contains(Point{-3, -3}), ShouldBeTrue)
+		So(diag.Contains(Point{-3, -3.1}), ShouldBeFalse)
+		So(diag.Intersects(vert), ShouldBeTrue)
+		So(diag.Intersects(Point{0, 1}.LineAlong(Vector{1, 1})), ShouldBeFalse)
+		So(vert.IsVertical(), ShouldBeTrue)
+		So(vert.IsHorizontal(), ShouldBeFalse)
+		So(diag.ClosestPoint(Point{0, 2}), ShouldResemble, Point{1, 1})
+		So(vert.ClosestPoint(Point{7, 3}), ShouldResemble, Point{2, 3})
+	})
+}
+
+func TestCircleOperators(t *testing.T) {
+
+	Convey("Given some circles", t, func() {
+		c1 := NewCircle(Origin, 1)
+		c2 := NewCircle(Point{5, 0}, 1)
+		c3 := NewCircle(Point{1, 0}, 1)
+		big := NewCircle(Origin, 3)
+
+		Convey("Distances should be between edges", func() {
+			So(c1.DistanceToCircle(c2), ShouldEqual, float64(3))
+			So(c1.DistanceToCircle(c3), ShouldEqual, float64(0))
+			So(c1.DistanceTo(Point{3, 4}), ShouldEqual, float64(4))
+			So(c1.DistanceTo(Point{0.5, 0}), ShouldEqual, float64(0))
+		})
+
+		Convey("Overlap and containment should include touching", func() {
+			So(c1.Overlaps(c3), ShouldBeTrue)
+			So(c1.Overlaps(NewCircle(Point{2, 0}, 1)), ShouldBeTrue)
+			So(c1.Overlaps(c2), ShouldBeFalse)
+			So(big.ContainsCircle(c3), ShouldBeTrue)
+			So(big.ContainsCircle(NewCircle(Point{2, 0}, 1)), ShouldBeTrue)
+			So(big.ContainsCircle(NewCircle(Point{2.5, 0}, 1)), ShouldBeFalse)
+			So(c3.ContainedBy(big), ShouldBeTrue)
+		})
+
+		Convey("Position should consider the radius", func() {
+			So(c1.LeftOf(c2), ShouldBeTrue)
+			So(c1.LeftOf(c3), ShouldBeFalse)
+			So(c2.RightOf(c1), ShouldBeTrue)
+			So(c1.OverLeft(c3), ShouldBeTrue)
+			So(c3.OverRight(c1), ShouldBeTrue)
+			So(c1.Below(NewCircle(Point{0, 3}, 1)), ShouldBeTrue)
+			So(NewCircle(Point{0, 3}, 1).Above(c1), ShouldBeTrue)
+			So(c1.OverBelow(big), ShouldBeTrue)
+			So(c1.OverAbove(big), ShouldBeTrue)
+		})
+
+		Convey("Sameness should be fuzzy", func() {
+			So(c1.Same(NewCircle(Point{1e-9, 0}, 1+1e-9)), ShouldBeTrue)
+			So(c1.Same(c3), ShouldBeFalse)
+			So(c1.Center(), ShouldResemble, Origin)
+		})
+	})
+}
+
+func TestPathOperators(t *testing.T) {
+
+	Convey("Given some paths", t, func() {
+		open := NewPath(Point{0, 0}, Point{-10, 0}, Point{-10, 10})
+		closed := NewClosedPath(Point{0, 0}, Point{-10, 0}, Point{-10, 10})
+
+		So(open.Contains(Point{-5, 0}), ShouldBeTrue)
+		So(open.Contains(Point{-5, 5}), ShouldBeFalse)
+		So(closed.Contains(Point{-5, 5}), ShouldBeTrue)
+		So(closed.Contains(Point{-8, 3}), ShouldBeTrue)
+
+		So(open.DistanceTo(Point{0, 5}), ShouldEqual, float64(5))
+		So(closed.DistanceTo(Point{0, 5}), ShouldAlmostEqual, math.Sqrt(12.5))
+		So(NewClosedPath(Point{3, 4}).DistanceTo(Origin), ShouldEqual, float64(5))
+
+		crossing := NewPath(Point{-5, -5}, Point{-5, 5})
+		So(open.Intersects(crossing), ShouldBeTrue)
+		So(open.Intersects(NewPath(Point{1, 1}, Point{2, 2})), ShouldBeFalse)
+
+		d, ok := open.DistanceToPath(NewPath(Point{1, 1}, Point{1, 5}))
+		So(ok, ShouldBeTrue)
+		So(d, ShouldAlmostEqual, math.Sqrt(2))
+
+		_, ok = open.DistanceToPath(NewPath(Point{1, 1}))
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestPolygonOperators(t *testing.T) {
+
+	Convey("Given some polygons", t, func() {
+		square := NewPolygon(Point{0, 0}, Point{0, 4}, Point{4, 4}, Point{4, 0})
+		small := NewPolygon(Point{1, 1}, Point{1, 2}, Point{2, 2}, Point{2, 1})
+		// a "U" shape, open at the top
+		u := NewPolygon(Point{0, 0}, Point{0, 3}, Point{1, 3}, Point{1, 1}, Point{2, 1}, Point{2, 3}, Point{3, 3}, Point{3, 0})
+		notch := NewPolygon(Point{1.2, 1.5}, Point{1.2, 2.5}, Point{1.8, 2.5}, Point{1.8, 1.5})
+		arm := NewPolygon(Point{0.2, 0.5}, Point{0.2, 2.5}, Point{0.8, 2.5}, Point{0.8, 0.5})
+		bridge := NewPolygon(Point{0.5, 2}, Point{0.5, 2.5}, Point{2.5, 2.5}, Point{2.5, 2})
+
+		Convey("Containment should follow the edges", func() {
+			So(square.ContainsPolygon(small), ShouldBeTrue)
+			So(square.ContainsPolygon(square), ShouldBeTrue)
+			So(small.ContainedBy(square), ShouldBeTrue)
+			So(small.ContainsPolygon(square), ShouldBeFalse)
+			So(u.ContainsPolygon(arm), ShouldBeTrue)
+			So(u.ContainsPolygon(bridge), ShouldBeFalse)
+			So(u.ContainsPolygon(notch), ShouldBeFalse)
+		})
+
+		Convey("Overlap should include touching, but not the inside of a notch", func() {
+			So(square.Overlaps(small), ShouldBeTrue)
+			So(small.Overlaps(square), ShouldBeTrue)
+			So(u.Overlaps(bridge), ShouldBeTrue)
+			So(u.Overlaps(notch), ShouldBeFalse)
+			So(square.Overlaps(NewPolygon(Point{4, 0}, Point{4, 4}, Point{6, 4}, Point{6, 0})), ShouldBeTrue)
+			So(square.Overlaps(NewPolygon(Point{5, 0}, Point{5, 4}, Point{6, 4})), ShouldBeFalse)
+		})
+
+		Convey("Sameness should ignore the starting vertex and direction", func() {
+			So(square.Same(NewPolygon(Point{4, 4}, Point{4, 0}, Point{0, 0}, Point{0, 4})), ShouldBeTrue)
+			So(square.Same(NewPolygon(Point{4, 0}, Point{4, 4}, Point{0, 4}, Point{0, 0})), ShouldBeTrue)
+			So(square.Same(NewPolygon(Point{0, 0}, Point{4, 4}, Point{0, 4}, Point{4, 0})), ShouldBeFalse)
+			So(square.Same(small), ShouldBeFalse)
+		})
+
+		Convey("Position should use the bounding boxes", func() {
+			right := NewPolygon(Point{5, 0}, Point{5, 4}, Point{6, 4})
+			So(square.LeftOf(right), ShouldBeTrue)
+			So(right.RightOf(square), ShouldBeTrue)
+			So(small.OverLeft(square), ShouldBeTrue)
+			So(small.OverRight(square), ShouldBeTrue)
+			So(small.OverBelow(square), ShouldBeTrue)
+			So(small.OverAbove(square), ShouldBeTrue)
+			So(small.Below(square), ShouldBeFalse)
+			So(small.Above(NewPolygon(Point{0, -1}, Point{1, 0}, Point{2, -1})), ShouldBeTrue)
+		})
+
+		Convey("Center and distance should match postgres", func() {
+			So(square.Center(), ShouldResemble, Point{2, 2})
+			So(square.DistanceTo(Point{6, 2}), ShouldEqual, float64(2))
+			So(square.DistanceTo(Point{1, 1}), ShouldEqual, float64(0))
+			So(u.DistanceTo(Point{1.5, 2}), ShouldEqual, float64(0.5))
+		})
+	})
+}
+
+func TestTolerance(t *testing.T) {
+
+	Convey("Given the postgres tolerance", t, func() {
+
+		Convey("Predicates should treat close coordinates as equal", func() {
+			So(Origin.Same(Point{1e-7, -1e-7}), ShouldBeTrue)
+			So(Origin.LeftOf(Point{1e-7, 0}), ShouldBeFalse)
+			So(NewSegment(Origin, Point{1, 1}).Contains(Point{0.5, 0.5 + 1e-7}), ShouldBeTrue)
+		})
+
+		Convey("Comparisons postgres makes exactly should stay exact", func() {
+			So(NewBox(Origin, Point{1, 1}).Contains(Point{1, 0.5}), ShouldBeTrue)
+			So(NewBox(Origin, Point{1, 1}).Contains(Point{1 + 1e-7, 0.5}), ShouldBeFalse)
+			So(NewCircle(Origin, 1).Contains(Point{1, 0}), ShouldBeTrue)
+			So(NewCircle(Origin, 1).Contains(Point{1 + 1e-7, 0}), ShouldBeFalse)
+			So(NewPolygon(Origin, Point{1, 1}, Point{1, 0}).LeftOf(NewPolygon(Point{1 + 1e-7, 0}, Point{2, 1}, Point{2, 0})), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given another tolerance for a call", t, func() {
+		So(Origin.ApproxEqual(Point{1e-7, -1e-7}, Exact), ShouldBeFalse)
+		So(Origin.ApproxEqual(Point{0.005, 0}, 0.01), ShouldBeTrue)
+		So(Origin.ApproxEqual(Point{0.02, 0}, 0.01), ShouldBeFalse)
+
+		Convey("Other calls should still use the postgres tolerance", func() {
+			So(Origin.Same(Point{1e-7, 0}), ShouldBeTrue)
+			So(Origin.Same(Point{0.005, 0}), ShouldBeFalse)
+		})
+	})
+
+	Convey("ApproxEqual should use its own tolerance, not PostgresEpsilon", t, func() {
+		So(Origin.ApproxEqual(Point{0.1, -0.1}, 0.1), ShouldBeTrue)
+		So(Origin.ApproxEqual(Point{0.1, -0.2}, 0.1), ShouldBeFalse)
+		So(Origin.ApproxEqual(Point{1e-9, 0}, Exact), ShouldBeFalse)
+
+		nan := Point{math.NaN(), math.NaN()}
+		So(nan.ApproxEqual(nan, Exact), ShouldBeTrue)
+		So(nan.ApproxEqual(Origin, 1), ShouldBeFalse)
+
+		So(Vector{1, 2}.ApproxEqual(Vector{1.05, 2}, 0.1), ShouldBeTrue)
+		So(Vector{1, 2}.ApproxEqual(Vector{2, 1}, 0.1), ShouldBeFalse)
+
+		s := NewSegment(Origin, Point{1, 1})
+		So(s.ApproxEqual(NewSegment(Point{0.01, 0}, Point{1, 1.01}), 0.1), ShouldBeTrue)
+		So(s.ApproxEqual(s.Flip(), 0.1), ShouldBeFalse)
+
+		b := NewBox(Origin, Point{1, 1})
+		So(b.ApproxEqual(NewBox(Point{0, 0.01}, Point{1.01, 1}), 0.1), ShouldBeTrue)
+		So(b.ApproxEqual(NewBox(Origin, Point{2, 2}), 0.1), ShouldBeFalse)
+
+		c := NewCircle(Origin, 1)
+		So(c.ApproxEqual(NewCircle(Point{0.01, 0}, 1.01), 0.1), ShouldBeTrue)
+		So(c.ApproxEqual(NewCircle(Origin, 2), 0.1), ShouldBeFalse)
+
+		pa := NewPath(Origin, Point{1, 1}, Point{2, 0})
+		So(pa.ApproxEqual(NewPath(Origin, Point{1, 1.01}, Point{2, 0}), 0.1), ShouldBeTrue)
+		So(pa.ApproxEqual(NewClosedPath(Origin, Point{1, 1}, Point{2, 0}), 0.1), ShouldBeFalse)
+		So(pa.ApproxEqual(pa.Reverse(), 0.1), ShouldBeFalse)
+
+		pg := NewPolygon(Origin, Point{1, 1}, Point{2, 0})
+		So(pg.ApproxEqual(NewPolygon(Origin, Point{1, 1.01}, Point{2, 0}), 0.1), ShouldBeTrue)
+		So(pg.ApproxEqual(NewPolygon(Point{1, 1}, Point{2, 0}, Origin), 0.1), ShouldBeFalse)
+		So(pg.Same(NewPolygon(Point{1, 1}, Point{2, 0}, Origin)), ShouldBeTrue)
+
+		l := NewLineCoefficients(1, -1, 0)
+		So(l.ApproxEqual(NewLineCoefficients(-2, 2, 0), Exact), ShouldBeTrue)
+		So(l.ApproxEqual(NewLineCoefficients(2, -2.1, 0), 0.1), ShouldBeTrue)
+		So(l.ApproxEqual(NewLineCoefficients(1, -1, 1), 0.1), ShouldBeFalse)
+		So(l.Same(NewLine(Point{-1, -1}, Point{3, 3})), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,106 @@
+package pgxgeometry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gregb/geometry"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// CopyWriter loads rows of geometry values into a table with COPY, which is
+// much faster than inserting them one at a time.  Rows are buffered, and
+// sent as one COPY statement for each batch.  Each batch commits on its own
+// unless the connection is in a transaction, so use one for all or nothing.
+//
+// With pgx.Conn, or a pgxpool connection, pass conn.PgConn():
+//
+//	w := pgxgeometry.NewCopyWriter(ctx, conn.PgConn(), pgx.Identifier{"stops"}, []string{"location"}, geometry.CopyBinary, 10000)
+//	for _, p := range points {
+//		if err := w.Write(p); err != nil { ... }
+//	}
+//	err := w.Close()
+type CopyWriter struct {
+	ctx       context.Context
+	conn      *pgconn.PgConn
+	sql       string
+	format    geometry.CopyFormat
+	batchSize int
+	buf       bytes.Buffer
+	enc       *geometry.CopyEncoder
+	written   int64
+}
+
+// NewCopyWriter returns a writer which copies rows into the named columns of
+// a table, in the given format.  A batch is sent after every batchSize rows,
+// or if batchSize is zero, only when the writer is flushed or closed.
+func NewCopyWriter(ctx context.Context, conn *pgconn.PgConn, table pgx.Identifier, columns []string, format geometry.CopyFormat, batchSize int) *CopyWriter {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = pgx.Identifier{c}.Sanitize()
+	}
+
+	w := &CopyWriter{
+		ctx:       ctx,
+		conn:      conn,
+		sql:       fmt.Sprintf("COPY %s (%s) FROM STDIN (FORMAT %s)", table.Sanitize(), strings.Join(quoted, ", "), format),
+		format:    format,
+		batchSize: batchSize,
+	}
+	w.enc = geometry.NewCopyEncoder(&w.buf, format)
+
+	return w
+}
+
+// Write buffers one row, with a value for each column, and sends the batch
+// if it is full.  A nil value is NULL.
+func (w *CopyWriter) Write(row ...geometry.Operand) error {
+	if err := w.enc.Encode(row...); err != nil {
+		return err
+	}
+
+	if w.batchSize > 0 && w.enc.Rows() >= int64(w.batchSize) {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Flush sends any buffered rows.
+func (w *CopyWriter) Flush() error {
+	if w.enc.Rows() == 0 {
+		return nil
+	}
+
+	err := w.enc.Close()
+
+	if err == nil {
+		var tag pgconn.CommandTag
+		tag, err = w.conn.CopyFrom(w.ctx, &w.buf, w.sql)
+		w.written += tag.RowsAffected()
+	}
+
+	w.buf.Reset()
+	w.enc = geometry.NewCopyEncoder(&w.buf, w.format)
+
+	return err
+}
+
+// Close sends any buffered rows.  The connection is left open.
+func (w *CopyWriter) Close() error {
+	return w.Flush()
+}
+
+// Written returns the number of rows the server has accepted.  Rows which
+// are still buffered are not counted.
+func (w *CopyWriter) Written() int64 {
+	return w.written
+}
+
+// SQL returns the COPY statement sent for each batch.
+func (w *CopyWriter) SQL() string {
+	return w.sql
+}
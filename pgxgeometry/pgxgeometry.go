@@ -0,0 +1,305 @@
+// Package pgxgeometry lets pgx v5 send and receive the geometry types over
+// its native protocol, in both the text and binary formats.
+//
+// Register the types on each connection, for example from a pool's
+// AfterConnect hook:
+//
+//	pgxgeometry.RegisterTypes(conn.TypeMap())
+//
+// after which values like geometry.Box can be used as query arguments, and
+// scanned into directly with rows.Scan(&box).  The nullable types, and slices
+// of each type for the postgres array types, are supported too.
+package pgxgeometry
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/gregb/geometry"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// the Go types handled for each postgres type, with their nullable wrappers
+var types = []struct {
+	name     string
+	oid      uint32
+	arrayOID uint32
+	values   []interface{}
+}{
+	{"point", pgtype.PointOID, pgtype.PointArrayOID, []interface{}{geometry.Point{}, geometry.Vector{}, geometry.NullPoint{}, geometry.NullVector{}}},
+	{"lseg", pgtype.LsegOID, pgtype.LsegArrayOID, []interface{}{geometry.Segment{}, geometry.NullSegment{}}},
+	{"box", pgtype.BoxOID, pgtype.BoxArrayOID, []interface{}{geometry.Box{}, geometry.NullBox{}}},
+	{"circle", pgtype.CircleOID, pgtype.CircleArrayOID, []interface{}{geometry.Circle{}, geometry.NullCircle{}}},
+	{"path", pgtype.PathOID, pgtype.PathArrayOID, []interface{}{geometry.Path{}, geometry.NullPath{}}},
+	{"polygon", pgtype.PolygonOID, pgtype.PolygonArrayOID, []interface{}{geometry.Polygon{}, geometry.NullPolygon{}}},
+	{"line", pgtype.LineOID, pgtype.LineArrayOID, []interface{}{geometry.Line{}, geometry.NullLine{}}},
+}
+
+// RegisterTypes registers codecs for the geometry types, and arrays of them,
+// with m.  Other Go types, like pgtype.Box, are still handled by pgx's own
+// codecs.
+func RegisterTypes(m *pgtype.Map) {
+	for _, t := range types {
+		dt, ok := m.TypeForOID(t.oid)
+		if !ok {
+			panic(fmt.Sprintf("pgxgeometry: pgx has no type for %s", t.name))
+		}
+
+		c := &Codec{Codec: dt.Codec}
+		for _, v := range t.values {
+			c.types = append(c.types, reflect.TypeOf(v))
+		}
+
+		geometryType := &pgtype.Type{Name: t.name, OID: t.oid, Codec: c}
+		m.RegisterType(geometryType)
+
+		var arrayCodec pgtype.Codec = &pgtype.ArrayCodec{ElementType: geometryType}
+		if t.oid == pgtype.BoxOID {
+			arrayCodec = &boxArrayCodec{ArrayCodec: arrayCodec.(*pgtype.ArrayCodec)}
+		}
+		m.RegisterType(&pgtype.Type{Name: "_" + t.name, OID: t.arrayOID, Codec: arrayCodec})
+
+		for _, v := range t.values {
+			m.RegisterDefaultPgType(v, t.name)
+		}
+	}
+}
+
+// Codec encodes and scans the geometry types for a single postgres type,
+// with their MarshalBinary and Value methods to encode, and UnmarshalBinary
+// and Scan to decode.  Any other Go type is passed on to the wrapped codec.
+type Codec struct {
+	pgtype.Codec
+	types []reflect.Type
+}
+
+// handles returns whether v is one of the codec's Go types, or if pointer is
+// set, a pointer to one.
+func (c *Codec) handles(v interface{}, pointer bool) bool {
+	rt := reflect.TypeOf(v)
+	for _, t := range c.types {
+		if pointer && rt == reflect.PointerTo(t) || !pointer && rt == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value interface{}) pgtype.EncodePlan {
+	if !c.handles(value, false) {
+		return c.Codec.PlanEncode(m, oid, format, value)
+	}
+
+	switch format {
+	case pgtype.BinaryFormatCode:
+		return encodePlanBinary{}
+	case pgtype.TextFormatCode:
+		return encodePlanText{}
+	}
+
+	return nil
+}
+
+func (c *Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target interface{}) pgtype.ScanPlan {
+	if !c.handles(target, true) {
+		return c.Codec.PlanScan(m, oid, format, target)
+	}
+
+	switch format {
+	case pgtype.BinaryFormatCode:
+		return scanPlanBinary{}
+	case pgtype.TextFormatCode:
+		return scanPlanText{}
+	}
+
+	return nil
+}
+
+// boxArrayCodec is the codec for box[], whose elements postgres delimits
+// with ';' in the text format rather than the ',' pgx always uses.  Slices
+// of boxes are written and parsed by geometry.BoxArray in the text format,
+// and everything else is passed on to the wrapped codec.
+type boxArrayCodec struct {
+	*pgtype.ArrayCodec
+}
+
+// boxArray returns v as a geometry.BoxArray, or a pointer to one if pointer
+// is set, and whether it is a slice of boxes.
+func boxArray(v interface{}, pointer bool) (interface{}, bool) {
+	switch a := v.(type) {
+	case []geometry.Box:
+		return geometry.BoxArray(a), !pointer
+	case geometry.BoxArray:
+		return a, !pointer
+	case *[]geometry.Box:
+		return (*geometry.BoxArray)(a), pointer
+	case *geometry.BoxArray:
+		return a, pointer
+	}
+	return nil, false
+}
+
+func (c *boxArrayCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value interface{}) pgtype.EncodePlan {
+	if _, ok := boxArray(value, false); !ok || format != pgtype.TextFormatCode {
+		return c.ArrayCodec.PlanEncode(m, oid, format, value)
+	}
+	return encodePlanBoxArray{}
+}
+
+func (c *boxArrayCodec) PlanScan(m *pgtype.Map, oid uint32, format int16, target interface{}) pgtype.ScanPlan {
+	if _, ok := boxArray(target, true); !ok || format != pgtype.TextFormatCode {
+		return c.ArrayCodec.PlanScan(m, oid, format, target)
+	}
+	return scanPlanBoxArray{}
+}
+
+// ----------
+
+type encodePlanBinary struct{}
+
+func (encodePlanBinary) Encode(value interface{}, buf []byte) ([]byte, error) {
+	value, valid := unwrap(value)
+	if !valid {
+		return nil, nil
+	}
+
+	b, err := value.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, b...), nil
+}
+
+type encodePlanText struct{}
+
+func (encodePlanText) Encode(value interface{}, buf []byte) ([]byte, error) {
+	value, valid := unwrap(value)
+	if !valid {
+		return nil, nil
+	}
+
+	v, err := value.(driver.Valuer).Value()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, v.([]byte)...), nil
+}
+
+type scanPlanBinary struct{}
+
+func (scanPlanBinary) Scan(src []byte, target interface{}) error {
+	return scan(src, target, func(dst interface{}) error {
+		return dst.(encoding.BinaryUnmarshaler).UnmarshalBinary(src)
+	})
+}
+
+type scanPlanText struct{}
+
+func (scanPlanText) Scan(src []byte, target interface{}) error {
+	return scan(src, target, func(dst interface{}) error {
+		return dst.(sql.Scanner).Scan(src)
+	})
+}
+
+type encodePlanBoxArray struct{}
+
+func (encodePlanBoxArray) Encode(value interface{}, buf []byte) ([]byte, error) {
+	a, _ := boxArray(value, false)
+
+	v, err := a.(geometry.BoxArray).Value()
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	return append(buf, v.([]byte)...), nil
+}
+
+type scanPlanBoxArray struct{}
+
+func (scanPlanBoxArray) Scan(src []byte, target interface{}) error {
+	a, _ := boxArray(target, true)
+
+	// a nil src is NULL, and scans as a nil slice
+	if src == nil {
+		return a.(*geometry.BoxArray).Scan(nil)
+	}
+	return a.(*geometry.BoxArray).Scan(src)
+}
+
+// scan decodes src into target, or into the value held by a nullable target,
+// whose validity is set as its own Scan method would.
+func scan(src []byte, target interface{}, decode func(dst interface{}) error) error {
+	dst, valid := unwrapTarget(target)
+
+	if src == nil {
+		if valid == nil {
+			return fmt.Errorf("cannot scan NULL into %T", target)
+		}
+		*valid = false
+		return nil
+	}
+
+	err := decode(dst)
+
+	if valid != nil {
+		*valid = err == nil
+	}
+
+	return err
+}
+
+// ----------
+
+// unwrap returns the value held by one of the nullable types, and whether
+// it is valid.  Other values are returned as they are.
+func unwrap(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case geometry.NullPoint:
+		return v.Point, v.Valid
+	case geometry.NullVector:
+		return v.Vector, v.Valid
+	case geometry.NullSegment:
+		return v.Segment, v.Valid
+	case geometry.NullBox:
+		return v.Box, v.Valid
+	case geometry.NullCircle:
+		return v.Circle, v.Valid
+	case geometry.NullPath:
+		return v.Path, v.Valid
+	case geometry.NullPolygon:
+		return v.Polygon, v.Valid
+	case geometry.NullLine:
+		return v.Line, v.Valid
+	}
+	return value, true
+}
+
+// unwrapTarget returns pointers to the value held by one of the nullable
+// types, and to its Valid field.  Other targets are returned as they are,
+// without a Valid field.
+func unwrapTarget(target interface{}) (interface{}, *bool) {
+	switch t := target.(type) {
+	case *geometry.NullPoint:
+		return &t.Point, &t.Valid
+	case *geometry.NullVector:
+		return &t.Vector, &t.Valid
+	case *geometry.NullSegment:
+		return &t.Segment, &t.Valid
+	case *geometry.NullBox:
+		return &t.Box, &t.Valid
+	case *geometry.NullCircle:
+		return &t.Circle, &t.Valid
+	case *geometry.NullPath:
+		return &t.Path, &t.Valid
+	case *geometry.NullPolygon:
+		return &t.Polygon, &t.Valid
+	case *geometry.NullLine:
+		return &t.Line, &t.Valid
+	}
+	return target, nil
+}
@@ -5,51 +5,72 @@ package geometry
 import (
 	"database/sql/driver"
 	"fmt"
-	"strconv"
 )
 
 // Checks that the number of floats returned by the sql driver matches expectations.
 // Src is expected to be a []float64, but the typecast is done here to consolidate error
 // checking so that each type's Scan() method does not have to do it itself.
-func expectFloats(src interface{}, expected int) ([]float64, error) {
+// Typ is the name of the postgres type, for errors.
+func expectFloats(src interface{}, typ string, expected int) ([]float64, error) {
 
 	floats, ok := src.([]float64)
 
 	if !ok {
-		return nil, fmt.Errorf("Expected []float64 from driver, got %T instead", src)
+		return nil, &SourceError{Type: typ, Want: "[]float64, []byte or string", Got: src}
 	}
 
 	// if positive, expect exactly that number
 	if expected > 0 {
 		if len(floats) != expected {
-			return nil, fmt.Errorf("Expected %d floats while parsing geometry, but got %d instead", expected, len(floats))
+			return nil, &FloatCountError{Type: typ, Want: expected, Got: len(floats)}
 		}
 	} else {
 		// otherwise, any multiple of |expected| is ok
 		// if expected == -1, then ANY amount is ok
 		extra := len(floats) % (-expected)
 		if extra != 0 {
-			return nil, fmt.Errorf("Expected a multiple of %d floats while parsing geometry, but got %d instead", -expected, len(floats))
+			return nil, &FloatCountError{Type: typ, Want: -expected, Got: len(floats), Multiple: true}
 		}
 	}
 
 	return floats, nil
 }
 
+// Like expectFloats, but also accepts the postgres text representation sent by
+// stock drivers (as either []byte or string), which is converted to floats by
+// the supplied decode function before the count is checked.
+func scanFloats(src interface{}, typ string, expected int, decode func(string) ([]float64, error)) ([]float64, error) {
+	switch s := src.(type) {
+	case []byte:
+		return scanFloats(string(s), typ, expected, decode)
+	case string:
+		floats, err := decode(s)
+		if err != nil {
+			return nil, err
+		}
+		return expectFloats(floats, typ, expected)
+	}
+
+	return expectFloats(src, typ, expected)
+}
+
 // assert that types implement driver.Valuer is a pq.Encoder
 var _ driver.Valuer = Point{}
 var _ driver.Valuer = Vector{}
 var _ driver.Valuer = Segment{}
 var _ driver.Valuer = Circle{}
 var _ driver.Valuer = Box{}
+var _ driver.Valuer = Path{}
+var _ driver.Valuer = Polygon{}
+var _ driver.Valuer = Line{}
 
 // ----------
 
 func (p *Point) Scan(src interface{}) error {
-	floats, err := expectFloats(src, 2)
+	floats, err := scanFloats(src, "point", 2, decodePoint)
 
 	if err != nil {
-		return fmt.Errorf("Error while parsing data for Point: %s", err)
+		return fmt.Errorf("Error while parsing data for Point: %w", err)
 	}
 
 	p.x = floats[0]
@@ -59,23 +80,16 @@ func (p *Point) Scan(src interface{}) error {
 }
 
 func (p Point) Value() (driver.Value, error) {
-	b := make([]byte, 0, 10)
-	b = append(b, '(')
-	b = strconv.AppendFloat(b, p.x, 'g', -1, 64)
-	b = append(b, ',')
-	b = strconv.AppendFloat(b, p.y, 'g', -1, 64)
-	b = append(b, ')')
-
-	return b, nil
+	return p.MarshalText()
 }
 
 // ----------
 
 func (v *Vector) Scan(src interface{}) error {
-	floats, err := expectFloats(src, 2)
+	floats, err := scanFloats(src, "point", 2, decodePoint)
 
 	if err != nil {
-		return fmt.Errorf("Error while parsing data for Vector: %s", err)
+		return fmt.Errorf("Error while parsing data for Vector: %w", err)
 	}
 
 	v.x = floats[0]
@@ -85,23 +99,16 @@ func (v *Vector) Scan(src interface{}) error {
 }
 
 func (v Vector) Value() (driver.Value, error) {
-	b := make([]byte, 0, 10)
-	b = append(b, '(')
-	b = strconv.AppendFloat(b, v.x, 'g', -1, 64)
-	b = append(b, ',')
-	b = strconv.AppendFloat(b, v.y, 'g', -1, 64)
-	b = append(b, ')')
-
-	return b, nil
+	return v.MarshalText()
 }
 
 // ----------
 
 func (s *Segment) Scan(src interface{}) error {
-	floats, err := expectFloats(src, 4)
+	floats, err := scanFloats(src, "lseg", 4, decodeSegment)
 
 	if err != nil {
-		return fmt.Errorf("Error while parsing data for Segment: %s", err)
+		return fmt.Errorf("Error while parsing data for Segment: %w", err)
 	}
 
 	s[0].x = floats[0]
@@ -113,27 +120,16 @@ func (s *Segment) Scan(src interface{}) error {
 }
 
 func (s Segment) Value() (driver.Value, error) {
-	b := make([]byte, 0, 10)
-	b = append(b, '[', '(')
-	b = strconv.AppendFloat(b, s[0].x, 'g', -1, 64)
-	b = append(b, ',')
-	b = strconv.AppendFloat(b, s[0].y, 'g', -1, 64)
-	b = append(b, ')', ',', '(')
-	b = strconv.AppendFloat(b, s[1].x, 'g', -1, 64)
-	b = append(b, ',')
-	b = strconv.AppendFloat(b, s[1].y, 'g', -1, 64)
-	b = append(b, ')', ']')
-
-	return b, nil
+	return s.MarshalText()
 }
 
 // ----------
 
 func (b *Box) Scan(src interface{}) error {
-	floats, err := expectFloats(src, 4)
+	floats, err := scanFloats(src, "box", 4, decodeBox)
 
 	if err != nil {
-		return fmt.Errorf("Error while parsing data for Box: %s", err)
+		return fmt.Errorf("Error while parsing data for Box: %w", err)
 	}
 
 	b[0].x = floats[0]
@@ -145,27 +141,16 @@ func (b *Box) Scan(src interface{}) error {
 }
 
 func (b Box) Value() (driver.Value, error) {
-	by := make([]byte, 0, 10)
-	by = append(by, '(', '(')
-	by = strconv.AppendFloat(by, b[0].x, 'g', -1, 64)
-	by = append(by, ',')
-	by = strconv.AppendFloat(by, b[0].y, 'g', -1, 64)
-	by = append(by, ')', ',', '(')
-	by = strconv.AppendFloat(by, b[1].x, 'g', -1, 64)
-	by = append(by, ',')
-	by = strconv.AppendFloat(by, b[1].y, 'g', -1, 64)
-	by = append(by, ')', ')')
-
-	return by, nil
+	return b.MarshalText()
 }
 
 // ----------
 
 func (c *Circle) Scan(src interface{}) error {
-	floats, err := expectFloats(src, 3)
+	floats, err := scanFloats(src, "circle", 3, decodeCircle)
 
 	if err != nil {
-		return fmt.Errorf("Error while parsing data for Circle: %s", err)
+		return fmt.Errorf("Error while parsing data for Circle: %w", err)
 	}
 
 	c.center.x = floats[0]
@@ -176,14 +161,69 @@ func (c *Circle) Scan(src interface{}) error {
 }
 
 func (c Circle) Value() (driver.Value, error) {
-	b := make([]byte, 0, 10)
-	b = append(b, '<', '(')
-	b = strconv.AppendFloat(b, c.center.x, 'g', -1, 64)
-	b = append(b, ',')
-	b = strconv.AppendFloat(b, c.center.y, 'g', -1, 64)
-	b = append(b, ')', ',')
-	b = strconv.AppendFloat(b, c.radius, 'g', -1, 64)
-	b = append(b, '>')
-
-	return b, nil
+	return c.MarshalText()
+}
+
+// ----------
+
+func (p *Path) Scan(src interface{}) error {
+	// a []float64 from the driver carries no closed flag, so is read as open
+	closed := false
+
+	floats, err := scanFloats(src, "path", -2, func(s string) ([]float64, error) {
+		floats, c, err := decodePath(s)
+		closed = c
+		return floats, err
+	})
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for Path: %w", err)
+	}
+
+	p.point = floatPoints(floats)
+	p.closed = closed
+
+	return nil
+}
+
+func (p Path) Value() (driver.Value, error) {
+	return p.MarshalText()
+}
+
+// ----------
+
+func (p *Polygon) Scan(src interface{}) error {
+	floats, err := scanFloats(src, "polygon", -2, decodePolygon)
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for Polygon: %w", err)
+	}
+
+	p.point = floatPoints(floats)
+
+	return nil
+}
+
+func (p Polygon) Value() (driver.Value, error) {
+	return p.MarshalText()
+}
+
+// ----------
+
+func (l *Line) Scan(src interface{}) error {
+	floats, err := scanFloats(src, "line", 3, decodeLine)
+
+	if err != nil {
+		return fmt.Errorf("Error while parsing data for Line: %w", err)
+	}
+
+	l.a = floats[0]
+	l.b = floats[1]
+	l.c = floats[2]
+
+	return nil
+}
+
+func (l Line) Value() (driver.Value, error) {
+	return l.MarshalText()
 }
@@ -18,6 +18,11 @@ CREATE TABLE IF NOT EXISTS geotest
   s lseg,
   b box,
   c circle,
+  pa path,
+  pg polygon,
+  l line,
+  pts point[],
+  bs box[],
   CONSTRAINT geotest_pkey PRIMARY KEY (id, t)
 )`
 
@@ -54,11 +59,11 @@ func TestExpectFloats(t *testing.T) {
 		f6 := []float64{1, 2, 3, 4, 5, 6}
 
 		Convey("Positive expectations should be met exactly", func() {
-			r1, e1 := expectFloats(f1, 1)
-			r2, e2 := expectFloats(f1, 2)
-			r3, e3 := expectFloats(f2, 2)
-			r4, e4 := expectFloats(f3, 1)
-			r5, e5 := expectFloats(f3, 3)
+			r1, e1 := expectFloats(f1, "point", 1)
+			r2, e2 := expectFloats(f1, "point", 2)
+			r3, e3 := expectFloats(f2, "point", 2)
+			r4, e4 := expectFloats(f3, "point", 1)
+			r5, e5 := expectFloats(f3, "point", 3)
 
 			So(r1, ShouldResemble, f1)
 			So(e1, ShouldBeNil)
@@ -77,13 +82,13 @@ func TestExpectFloats(t *testing.T) {
 		})
 
 		Convey("Negative expectations should be met in multiples", func() {
-			r1, e1 := expectFloats(f1, -1)
-			r2, e2 := expectFloats(f6, -1)
-			r3, e3 := expectFloats(f2, -2)
-			r4, e4 := expectFloats(f3, -2)
-			r5, e5 := expectFloats(f4, -2)
-			r6, e6 := expectFloats(f5, -2)
-			r7, e7 := expectFloats(f6, -2)
+			r1, e1 := expectFloats(f1, "point", -1)
+			r2, e2 := expectFloats(f6, "point", -1)
+			r3, e3 := expectFloats(f2, "point", -2)
+			r4, e4 := expectFloats(f3, "point", -2)
+			r5, e5 := expectFloats(f4, "point", -2)
+			r6, e6 := expectFloats(f5, "point", -2)
+			r7, e7 := expectFloats(f6, "point", -2)
 
 			So(r1, ShouldResemble, f1)
 			So(e1, ShouldBeNil)
@@ -110,7 +115,7 @@ func TestExpectFloats(t *testing.T) {
 		Convey("Non []float64 should return an error", func() {
 			s := "this isn't a float slice"
 
-			r, e := expectFloats(s, 2)
+			r, e := expectFloats(s, "point", 2)
 			So(r, ShouldBeNil)
 			So(e, ShouldNotBeNil)
 		})
@@ -213,3 +218,143 @@ func TestCircleRoundtrip(t *testing.T) {
 		})
 	})
 }
+
+func TestPathRoundtrip(t *testing.T) {
+	Convey("Given a postgres table with geometric datatypes", t, func() {
+
+		p1 := NewPath(Point{-1.2, -3.4}, Point{5.6, 7.8}, Point{123456789012345, 0.123456789012345})
+		p2 := NewClosedPath(Point{0, 0}, Point{0, 1}, Point{1, 1})
+
+		Convey("Test that paths can be written, read, and match", func() {
+			var r1, r2 Path
+
+			testRoundtrip(t, 8, "pa", p1, &r1)
+			So(r1, ShouldResemble, p1)
+
+			testRoundtrip(t, 9, "pa", p2, &r2)
+			So(r2, ShouldResemble, p2)
+		})
+	})
+}
+
+func TestPolygonRoundtrip(t *testing.T) {
+	Convey("Given a postgres table with geometric datatypes", t, func() {
+
+		p := NewPolygon(Point{-1.2, -3.4}, Point{5.6, 7.8}, Point{123456789012345, 0.123456789012345})
+
+		Convey("Test that polygons can be written, read, and match", func() {
+			var r Polygon
+			testRoundtrip(t, 10, "pg", p, &r)
+			So(r, ShouldResemble, p)
+		})
+	})
+}
+
+func TestLineRoundtrip(t *testing.T) {
+	Convey("Given a postgres table with geometric datatypes", t, func() {
+
+		l := NewLineCoefficients(1234.56789, -9876.54321, 0.123456789012345)
+
+		Convey("Test that lines can be written, read, and match", func() {
+			var r Line
+			testRoundtrip(t, 11, "l", l, &r)
+			So(r, ShouldResemble, l)
+		})
+	})
+}
+
+func TestArrayRoundtrip(t *testing.T) {
+	Convey("Given a postgres table with geometric datatypes", t, func() {
+
+		ps := PointArray{NewPoint(1234.56789, -9876.54321), NewPoint(0, 0)}
+		bs := BoxArray{NewBox(Point{-1.2, -3.4}, Point{5.6, 7.8}), NewBox(Point{0, 0}, Point{1, 1})}
+
+		Convey("Test that arrays can be written, read, and match", func() {
+			var r1 PointArray
+			testRoundtrip(t, 12, "pts", ps, &r1)
+			So(r1, ShouldResemble, ps)
+
+			var r2 BoxArray
+			testRoundtrip(t, 13, "bs", bs, &r2)
+			So(r2, ShouldResemble, bs)
+		})
+	})
+}
+
+func TestScanText(t *testing.T) {
+
+	Convey("Given the text representation sent by a stock driver", t, func() {
+
+		Convey("Points and vectors should scan from []byte and string", func() {
+			var p Point
+			So(p.Scan([]byte("(1,2)")), ShouldBeNil)
+			So(p, ShouldResemble, NewPoint(1, 2))
+
+			var v Vector
+			So(v.Scan("(-1.5,3e2)"), ShouldBeNil)
+			So(v, ShouldResemble, NewVector(-1.5, 300))
+		})
+
+		Convey("Segments should scan", func() {
+			var s Segment
+			So(s.Scan([]byte("[(1,2),(3,4)]")), ShouldBeNil)
+			So(s, ShouldResemble, NewSegment(Point{1, 2}, Point{3, 4}))
+		})
+
+		Convey("Boxes should scan", func() {
+			var b Box
+			So(b.Scan([]byte("(3,4),(1,2)")), ShouldBeNil)
+			So(b, ShouldResemble, NewBox(Point{1, 2}, Point{3, 4}))
+		})
+
+		Convey("Circles should scan", func() {
+			var c Circle
+			So(c.Scan([]byte("<(1,2),3>")), ShouldBeNil)
+			So(c, ShouldResemble, NewCircle(Point{1, 2}, 3))
+		})
+
+		Convey("Paths should scan, keeping whether they are closed", func() {
+			var p Path
+			So(p.Scan([]byte("[(1,2),(3,4)]")), ShouldBeNil)
+			So(p, ShouldResemble, NewPath(Point{1, 2}, Point{3, 4}))
+
+			So(p.Scan("((1,2),(3,4))"), ShouldBeNil)
+			So(p, ShouldResemble, NewClosedPath(Point{1, 2}, Point{3, 4}))
+		})
+
+		Convey("Polygons should scan", func() {
+			var p Polygon
+			So(p.Scan([]byte("((0,0),(0,1),(1,0))")), ShouldBeNil)
+			So(p, ShouldResemble, NewPolygon(Point{0, 0}, Point{0, 1}, Point{1, 0}))
+		})
+
+		Convey("Lines should scan", func() {
+			var l Line
+			So(l.Scan([]byte("{1,-1,0}")), ShouldBeNil)
+			So(l, ShouldResemble, NewLine(Origin, Point{1, 1}))
+		})
+
+		Convey("Values should scan back to the original", func() {
+			b := NewBox(Point{-1.2, -3.4}, Point{5.6, 7.8})
+			v, _ := b.Value()
+			var r Box
+			So(r.Scan(v), ShouldBeNil)
+			So(r, ShouldResemble, b)
+
+			for _, p := range []Path{NewPath(Point{1, 2}, Point{3, 4}), NewClosedPath(Point{1, 2}, Point{3, 4})} {
+				v, _ := p.Value()
+				var r Path
+				So(r.Scan(v), ShouldBeNil)
+				So(r, ShouldResemble, p)
+			}
+		})
+
+		Convey("Malformed text should return an error", func() {
+			var p Point
+			So(p.Scan("(1,2"), ShouldNotBeNil)
+
+			var c Circle
+			So(c.Scan([]byte("(1,2)")), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package geometry
+
+// Precision of the coordinates written in text formats: JSON, WKT, GeoJSON
+// and SVG.  The postgres text representation sent by Value, and binary
+// formats, are always exact, unless a value is bound to a precision with
+// Bind.
+
+import (
+	"database/sql/driver"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type precisionMode byte
+
+const (
+	precisionLossless precisionMode = iota
+	precisionDecimals
+	precisionSignificant
+	precisionGrid
+)
+
+// Precision is how coordinates are rounded when written as text.  It is set
+// for each call, on JsonOptions, WKTEncoder, GeoJSONEncoder or SVG, or by a
+// precision in a fmt verb, or for the SQL text of a value, with Bind.  The
+// zero Precision is Lossless.
+type Precision struct {
+	mode   precisionMode
+	digits int
+	grid   float64
+}
+
+// Lossless writes the shortest text which reads back as exactly the same
+// float64, so values round trip without change.  It is always used by Value
+// and MarshalText, so that values written to the database are never
+// rounded.
+var Lossless = Precision{}
+
+// Decimals rounds to n digits after the decimal point, dropping trailing
+// zeros, so 1.2345 is written as 1.23 with 2 decimals, and 1.5 as 1.5.
+func Decimals(n int) Precision {
+	return Precision{mode: precisionDecimals, digits: n}
+}
+
+// Significant rounds to n significant digits, so 1234.5 is written as 1230
+// with 3 digits, and 0.00012345 as 0.000123.
+func Significant(n int) Precision {
+	return Precision{mode: precisionSignificant, digits: n}
+}
+
+// Grid snaps to the nearest multiple of size, so 1.3 is written as 1.5 with
+// a grid of 0.5.  Coordinates are written with no more decimals than size
+// has, so that snapping to a grid of 0.1 does not write 0.30000000000000004.
+func Grid(size float64) Precision {
+	return Precision{mode: precisionGrid, grid: size}
+}
+
+// Bind returns the value with the precision bound to it, whose Value method
+// sends the postgres text rounded to the precision.  It is itself an
+// Operand, so it can be an argument of an Expr too.
+func (p Precision) Bind(v Operand) PreciseValue {
+	return PreciseValue{Operand: v, Precision: p}
+}
+
+// PreciseValue is a value which is written to the database rounded to its
+// own precision, rather than exactly.
+type PreciseValue struct {
+	Operand   Operand
+	Precision Precision
+}
+
+// Implements driver.Valuer interface
+func (v PreciseValue) Value() (driver.Value, error) {
+	// for the error of a value which can't be written, like a path with no
+	// points
+	if _, err := v.Operand.Value(); err != nil {
+		return nil, err
+	}
+
+	return v.Operand.(formattable).appendText(nil, v.Precision), nil
+}
+
+func (v PreciseValue) pgType() string {
+	return v.Operand.pgType()
+}
+
+// appendFloat appends f rounded to the precision.
+func (p Precision) appendFloat(b []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.AppendFloat(b, f, 'g', -1, 64)
+	}
+
+	switch p.mode {
+	case precisionDecimals:
+		return appendDecimals(b, f, p.digits)
+	case precisionSignificant:
+		// round, then write the rounded value without an exponent where
+		// possible, as 1230 rather than 1.23e+03
+		f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', p.digits, 64), 64)
+		return strconv.AppendFloat(b, f, 'g', -1, 64)
+	case precisionGrid:
+		if p.grid <= 0 {
+			break
+		}
+		// the decimals of the grid size, as it is written
+		decimals := 0
+		if s := strconv.FormatFloat(p.grid, 'f', -1, 64); strings.Contains(s, ".") {
+			decimals = len(s) - strings.IndexByte(s, '.') - 1
+		}
+		return appendDecimals(b, math.Round(f/p.grid)*p.grid, decimals)
+	}
+
+	return strconv.AppendFloat(b, f, 'g', -1, 64)
+}
+
+// appends f with n decimals, without trailing zeros or a negative zero
+func appendDecimals(b []byte, f float64, n int) []byte {
+	start := len(b)
+	b = strconv.AppendFloat(b, f, 'f', n, 64)
+
+	if n > 0 {
+		for b[len(b)-1] == '0' {
+			b = b[:len(b)-1]
+		}
+		if b[len(b)-1] == '.' {
+			b = b[:len(b)-1]
+		}
+	}
+
+	if string(b[start:]) == "-0" {
+		b = append(b[:start], '0')
+	}
+
+	return b
+}
@@ -0,0 +1,116 @@
+package geometry
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestPrecision(t *testing.T) {
+
+	Convey("Given precisions", t, func() {
+		format := func(p Precision, f float64) string {
+			return string(p.appendFloat(nil, f))
+		}
+
+		// not a constant, so that it is 0.30000000000000004
+		tenth := 0.1
+		sum := tenth + 0.2
+
+		Convey("Decimals should round after the decimal point", func() {
+			So(format(Decimals(2), 1.2345), ShouldEqual, "1.23")
+			So(format(Decimals(2), 1.5), ShouldEqual, "1.5")
+			So(format(Decimals(2), 2), ShouldEqual, "2")
+			So(format(Decimals(2), -0.001), ShouldEqual, "0")
+			So(format(Decimals(0), 12.7), ShouldEqual, "13")
+		})
+
+		Convey("Significant should round to significant digits", func() {
+			So(format(Significant(3), 1234.5), ShouldEqual, "1230")
+			So(format(Significant(3), 0.00012345), ShouldEqual, "0.000123")
+			So(format(Significant(3), -2.5), ShouldEqual, "-2.5")
+		})
+
+		Convey("Grid should snap to multiples of the size", func() {
+			So(format(Grid(0.1), sum), ShouldEqual, "0.3")
+			So(format(Grid(0.5), 1.3), ShouldEqual, "1.5")
+			So(format(Grid(10), 14), ShouldEqual, "10")
+		})
+
+		Convey("Lossless should read back exactly", func() {
+			for _, f := range []float64{sum, math.Pi, 1e300, -5e-324} {
+				g, err := strconv.ParseFloat(format(Lossless, f), 64)
+				So(err, ShouldBeNil)
+				So(g, ShouldEqual, f)
+			}
+		})
+
+		Convey("Non-finite values should be written unrounded", func() {
+			So(format(Decimals(2), math.Inf(1)), ShouldEqual, "+Inf")
+			So(format(Grid(0.5), math.NaN()), ShouldEqual, "NaN")
+		})
+	})
+
+	Convey("Given a point", t, func() {
+		p := NewPoint(1.25, 2.04)
+
+		Convey("Value and MarshalText should always be exact", func() {
+			v, err := p.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, []byte("(1.25,2.04)"))
+
+			b, err := NewPoint(1.0/3, 0).MarshalText()
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "(0.3333333333333333,0)")
+		})
+
+		Convey("The text formats should be exact unless a precision is given", func() {
+			b, err := json.Marshal(p)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "[1.25,2.04]")
+
+			b, err = MarshalWKT(p)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "POINT(1.25 2.04)")
+
+			b, err = MarshalGeoJSON(p)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"Point","coordinates":[1.25,2.04]}`)
+		})
+
+		Convey("The precision of each call should round the coordinates", func() {
+			b, err := JsonOptions{Precision: Decimals(1)}.Marshal(NewCircle(p, 0.125))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "[[1.2,2],0.1]")
+
+			b, err = WKTEncoder{Precision: Decimals(1)}.Marshal(p)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "POINT(1.2 2)")
+
+			b, err = GeoJSONEncoder{Precision: Decimals(1)}.Marshal(p)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"type":"Point","coordinates":[1.2,2]}`)
+		})
+
+		Convey("A value bound to a precision should be written to the database rounded", func() {
+			v, err := Decimals(1).Bind(p).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, []byte("(1.2,2)"))
+
+			v, err = Grid(0.5).Bind(NewBox(p, Origin)).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, []byte("((1.5,2),(0,0))"))
+
+			where, args, err := Same("p", Decimals(1).Bind(p)).Build(1)
+			So(err, ShouldBeNil)
+			So(where, ShouldEqual, "(p ~= $1::point)")
+			So(args, ShouldResemble, []interface{}{"(1.2,2)"})
+
+			_, err = Decimals(1).Bind(Path{}).Value()
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,219 @@
+package geometry
+
+// A small builder for SQL conditions using the postgres geometric operators.
+// Arguments are encoded with each type's Value method, and their
+// placeholders are cast to the matching postgres type, so the server never
+// has to guess which operator was meant.
+//
+//	where, args, err := And(Overlaps("b", box), Not(Contains("c", p))).Build(1)
+//	rows, err := db.Query("SELECT id FROM shapes WHERE "+where, args...)
+//
+// Column names are copied into the SQL as they are, so they must never come
+// from user input.
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrEmptyExpr is returned by Build for a zero Expr, or one built from a
+// zero Expr, which has no SQL to write.
+var ErrEmptyExpr = errors.New("Empty expression")
+
+// An Operand is a geometry value which can be an argument of an Expr.
+type Operand interface {
+	driver.Valuer
+	pgType() string
+}
+
+func (Point) pgType() string   { return "point" }
+func (Vector) pgType() string  { return "point" }
+func (Segment) pgType() string { return "lseg" }
+func (Box) pgType() string     { return "box" }
+func (Circle) pgType() string  { return "circle" }
+func (Path) pgType() string    { return "path" }
+func (Polygon) pgType() string { return "polygon" }
+func (Line) pgType() string    { return "line" }
+
+// Expr is a fragment of SQL, and the arguments for its placeholders.  Any
+// error encoding an argument is kept until the Expr is built.
+type Expr struct {
+	sql  []string // the text around each placeholder, one more than args
+	args []interface{}
+	err  error
+}
+
+// Build returns the SQL of the expression, with placeholders numbered from
+// $first, and its arguments.  Start from a later number to follow the
+// arguments of the rest of a query.
+func (e Expr) Build(first int) (string, []interface{}, error) {
+	if e.err != nil {
+		return "", nil, e.err
+	}
+	if len(e.sql) == 0 {
+		return "", nil, ErrEmptyExpr
+	}
+
+	var b strings.Builder
+
+	for i, s := range e.sql {
+		if i > 0 {
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(first + i - 1))
+		}
+		b.WriteString(s)
+	}
+
+	return b.String(), e.args, nil
+}
+
+// String returns the SQL of the expression, with placeholders numbered
+// from $1, or a description of its error.
+func (e Expr) String() string {
+	s, _, err := e.Build(1)
+	if err != nil {
+		return fmt.Sprintf("!(%s)", err)
+	}
+	return s
+}
+
+// appends other expressions to e, separated by sep.  A zero expression is
+// skipped, leaving ErrEmptyExpr for Build.
+func (e Expr) join(sep string, exprs ...Expr) Expr {
+	for _, x := range exprs {
+		if e.err == nil {
+			e.err = x.err
+		}
+
+		if len(x.sql) == 0 {
+			if e.err == nil {
+				e.err = ErrEmptyExpr
+			}
+			continue
+		}
+
+		if len(e.sql) == 0 {
+			e.sql = append([]string{}, x.sql...)
+		} else {
+			last := len(e.sql) - 1
+			e.sql = append(e.sql[:last:last], e.sql[last]+sep+x.sql[0])
+			e.sql = append(e.sql, x.sql[1:]...)
+		}
+
+		e.args = append(e.args[:len(e.args):len(e.args)], x.args...)
+	}
+
+	return e
+}
+
+// wraps e in parentheses
+func (e Expr) wrap() Expr {
+	return Expr{sql: []string{"("}}.join("", e).join("", Expr{sql: []string{")"}})
+}
+
+// binds an operand as a placeholder, cast to its postgres type.  The text
+// from Value is passed as a string, which every driver sends as is.
+func bind(v Operand) Expr {
+	e := Expr{sql: []string{"", "::" + v.pgType()}}
+
+	val, err := v.Value()
+	if err != nil {
+		e.err = err
+		return e
+	}
+
+	if b, ok := val.([]byte); ok {
+		val = string(b)
+	}
+
+	e.args = []interface{}{val}
+	return e
+}
+
+// builds "(col op $n::type)"
+func operator(col, op string, v Operand) Expr {
+	return Expr{sql: []string{col + " " + op + " "}}.join("", bind(v)).wrap()
+}
+
+// And joins conditions which must all hold.  With no conditions, it is TRUE.
+func And(exprs ...Expr) Expr {
+	if len(exprs) == 0 {
+		return Expr{sql: []string{"TRUE"}}
+	}
+	return Expr{}.join(" AND ", exprs...).wrap()
+}
+
+// Or joins conditions of which any one must hold.  With no conditions, it
+// is FALSE.
+func Or(exprs ...Expr) Expr {
+	if len(exprs) == 0 {
+		return Expr{sql: []string{"FALSE"}}
+	}
+	return Expr{}.join(" OR ", exprs...).wrap()
+}
+
+// Not negates a condition.
+func Not(e Expr) Expr {
+	return Expr{sql: []string{"NOT "}}.join("", e).wrap()
+}
+
+// Same matches rows where the column is the same as v. (~=)
+func Same(col string, v Operand) Expr {
+	return operator(col, "~=", v)
+}
+
+// Overlaps matches rows where the column overlaps v. (&&)
+func Overlaps(col string, v Operand) Expr {
+	return operator(col, "&&", v)
+}
+
+// Contains matches rows where the column contains v. (@>)
+func Contains(col string, v Operand) Expr {
+	return operator(col, "@>", v)
+}
+
+// ContainedBy matches rows where the column is contained by v. (<@)
+func ContainedBy(col string, v Operand) Expr {
+	return operator(col, "<@", v)
+}
+
+// Intersects matches rows where the column intersects v. (?#)
+func Intersects(col string, v Operand) Expr {
+	return operator(col, "?#", v)
+}
+
+// LeftOf matches rows where the column is strictly left of v. (<<)
+func LeftOf(col string, v Operand) Expr {
+	return operator(col, "<<", v)
+}
+
+// RightOf matches rows where the column is strictly right of v. (>>)
+func RightOf(col string, v Operand) Expr {
+	return operator(col, ">>", v)
+}
+
+// Below matches rows where the column is strictly below v. (<<|)
+func Below(col string, v Operand) Expr {
+	return operator(col, "<<|", v)
+}
+
+// Above matches rows where the column is strictly above v. (|>>)
+func Above(col string, v Operand) Expr {
+	return operator(col, "|>>", v)
+}
+
+// Within matches rows where the column is no further than d from v.
+func Within(col string, v Operand, d float64) Expr {
+	dist := Expr{sql: []string{"", "::float8"}, args: []interface{}{d}}
+	return DistanceOrder(col, v).join(" <= ", dist).wrap()
+}
+
+// DistanceOrder is the distance from the column to v, for ordering rows
+// from nearest to furthest.  A GiST index on the column can return rows in
+// this order without sorting them. (<->)
+func DistanceOrder(col string, v Operand) Expr {
+	return operator(col, "<->", v)
+}
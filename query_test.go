@@ -0,0 +1,107 @@
+package geometry
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+
+	Convey("Given some geometry values", t, func() {
+		box := NewBox(Point{0, 0}, Point{2, 2})
+		circle := NewCircle(Point{1, 1}, 3)
+		p := NewPoint(1, 2)
+
+		Convey("Operators should cast their placeholders", func() {
+			sql, args, err := Overlaps("b", box).Build(1)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "(b && $1::box)")
+			So(args, ShouldResemble, []interface{}{"((2,2),(0,0))"})
+
+			sql, args, err = ContainedBy("p", circle).Build(1)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "(p <@ $1::circle)")
+			So(args, ShouldResemble, []interface{}{"<(1,1),3>"})
+
+			So(Same("s", NewSegment(p, Origin)).String(), ShouldEqual, "(s ~= $1::lseg)")
+			So(Contains("pg", p).String(), ShouldEqual, "(pg @> $1::point)")
+			So(Intersects("l", NewLineCoefficients(1, -1, 0)).String(), ShouldEqual, "(l ?# $1::line)")
+			So(LeftOf("b", box).String(), ShouldEqual, "(b << $1::box)")
+			So(RightOf("b", box).String(), ShouldEqual, "(b >> $1::box)")
+			So(Below("b", box).String(), ShouldEqual, "(b <<| $1::box)")
+			So(Above("b", box).String(), ShouldEqual, "(b |>> $1::box)")
+			So(Overlaps("pa", NewClosedPath(p, Origin, Point{2, 0})).String(), ShouldEqual, "(pa && $1::path)")
+			So(Overlaps("pg", NewPolygon(p, Origin, Point{2, 0})).String(), ShouldEqual, "(pg && $1::polygon)")
+			So(DistanceOrder("v", NewVector(3, 4)).String(), ShouldEqual, "(v <-> $1::point)")
+		})
+
+		Convey("Distances should be compared as float8", func() {
+			sql, args, err := Within("p", p, 2.5).Build(1)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "((p <-> $1::point) <= $2::float8)")
+			So(args, ShouldResemble, []interface{}{"(1,2)", 2.5})
+		})
+
+		Convey("Conditions should combine, numbering their placeholders in order", func() {
+			e := And(Overlaps("b", box), Or(Not(Contains("c", p)), ContainedBy("c", circle)))
+
+			sql, args, err := e.Build(3)
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "((b && $3::box) AND ((NOT (c @> $4::point)) OR (c <@ $5::circle)))")
+			So(args, ShouldResemble, []interface{}{"((2,2),(0,0))", "(1,2)", "<(1,1),3>"})
+		})
+
+		Convey("Combining should not change the original conditions", func() {
+			o := Overlaps("b", box)
+			a1 := And(o, Contains("b", p))
+			a2 := And(o, Contains("b", NewPoint(5, 5)))
+
+			So(o.String(), ShouldEqual, "(b && $1::box)")
+			So(a1.args[1], ShouldEqual, "(1,2)")
+			So(a2.args[1], ShouldEqual, "(5,5)")
+		})
+
+		Convey("Empty combinations should be constant", func() {
+			So(And().String(), ShouldEqual, "TRUE")
+			So(Or().String(), ShouldEqual, "FALSE")
+		})
+
+		Convey("Encoding errors should be returned by Build", func() {
+			_, _, err := And(Overlaps("b", box), Contains("pa", Path{})).Build(1)
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+
+		Convey("Empty expressions should be returned as errors by Build", func() {
+			for _, e := range []Expr{{}, Not(Expr{}), And(Expr{}, Overlaps("b", box)), Or(Overlaps("b", box), Expr{}), Expr{}.wrap()} {
+				_, _, err := e.Build(1)
+				So(err, ShouldEqual, ErrEmptyExpr)
+			}
+		})
+	})
+}
+
+func TestQueryDB(t *testing.T) {
+
+	Convey("Given rows in a postgres table", t, func() {
+		const id = 14
+
+		_, err := db.Exec("INSERT INTO geotest (id, t, p, b, c) VALUES ($1, $2, $3, $4, $5)",
+			id, testTime, NewPoint(1, 1), NewBox(Point{0, 0}, Point{2, 2}), NewCircle(Point{0, 0}, 1))
+		So(err, ShouldBeNil)
+
+		Convey("Built conditions should select them", func() {
+			where, args, err := And(
+				Overlaps("b", NewBox(Point{1, 1}, Point{3, 3})),
+				ContainedBy("p", NewCircle(Point{0, 0}, 2)),
+				Within("c", NewPoint(3, 0), 2),
+			).Build(3)
+			So(err, ShouldBeNil)
+
+			var n int
+			args = append([]interface{}{id, testTime}, args...)
+			So(db.QueryRow("SELECT count(*) FROM geotest WHERE id = $1 AND t = $2 AND "+where, args...).Scan(&n), ShouldBeNil)
+			So(n, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,342 @@
+package geometry
+
+// Drawing of shapes as SVG images, for looking at them while debugging.
+//
+// The package puts larger y at the top, as the box normal form does, while
+// SVG puts it at the bottom, so y coordinates are negated when written.
+// Strokes are drawn with vector-effect="non-scaling-stroke", so that their
+// width is in pixels whatever the scale of the scene.
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	svgDefaultWidth  = 400 // pixels
+	svgDefaultMargin = 10  // pixels
+	svgPointRadius   = 3   // pixels
+	svgArrowHead     = 8   // pixels, along each side of the head
+	svgArrowAngle    = math.Pi / 8
+)
+
+// SVGStyle holds the SVG presentation attributes of a shape, such as
+// "stroke", "fill" or "stroke-dasharray", which override the defaults of
+// a black stroke one pixel wide and no fill.  Points are filled black.
+type SVGStyle map[string]string
+
+// SVG is a scene of shapes, written as an SVG image whose viewBox fits the
+// bounds of all of them.  The zero SVG is an empty scene, ready to use.
+type SVG struct {
+	Width     int       // of the image in pixels, or 400 if zero; the height follows the scene
+	Height    int       // the most the image may be in pixels, or Width if zero
+	Margin    int       // around the scene in pixels, or 10 if zero
+	Precision Precision // of the coordinates, or Lossless if unset
+
+	shapes []svgShape
+}
+
+type svgShape struct {
+	shape  interface{} // a Point, Segment, Box, Circle, Path, Polygon or svgArrow
+	style  SVGStyle
+	bounds Box
+}
+
+// a vector drawn from a point
+type svgArrow struct {
+	from Point
+	v    Vector
+}
+
+// Add adds a Point, Segment, Box, Circle, Path or Polygon to the scene, drawn
+// with the style, which may be nil.  A Vector is drawn as an arrow from the
+// origin.  Shapes are drawn in the order they are added, so later shapes are
+// on top.
+func (s *SVG) Add(shape interface{}, style SVGStyle) error {
+	var typ string
+	var bounds Box
+
+	switch g := shape.(type) {
+	case Point:
+		typ, bounds = "point", Box{g, g}
+	case Vector:
+		return s.AddVector(Origin, g, style)
+	case Segment:
+		typ, bounds = "lseg", boundingBox(g[:])
+	case Box:
+		typ, bounds = "box", g
+	case Circle:
+		if g.radius < 0 {
+			return &GeometryError{Type: "circle", Reason: "radius cannot be negative"}
+		}
+		r := Vector{g.radius, g.radius}
+		typ, bounds = "circle", Box{g.center.Translate(r), g.center.Translate(r.Scale(-1))}
+	case Path:
+		if len(g.point) == 0 {
+			return &GeometryError{Type: "path", Reason: "cannot draw a path with no points"}
+		}
+		typ, bounds = "path", boundingBox(g.point)
+	case Polygon:
+		if len(g.point) == 0 {
+			return &GeometryError{Type: "polygon", Reason: "cannot draw a polygon with no points"}
+		}
+		typ, bounds = "polygon", boundingBox(g.point)
+	default:
+		return fmt.Errorf("Cannot draw %T as SVG", shape)
+	}
+
+	return s.add(typ, shape, style, bounds)
+}
+
+// AddVector adds a vector to the scene, drawn as an arrow from the point with
+// the style, which may be nil.
+func (s *SVG) AddVector(from Point, v Vector, style SVGStyle) error {
+	return s.add("vector", svgArrow{from: from, v: v}, style, boundingBox([]Point{from, from.Translate(v)}))
+}
+
+func (s *SVG) add(typ string, shape interface{}, style SVGStyle, bounds Box) error {
+	for _, f := range []float64{bounds[0].x, bounds[0].y, bounds[1].x, bounds[1].y} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return &GeometryError{Type: typ, Reason: "SVG coordinates must be finite"}
+		}
+	}
+
+	s.shapes = append(s.shapes, svgShape{shape: shape, style: style, bounds: bounds})
+	return nil
+}
+
+// Bounds returns the bounding box of all of the shapes in the scene, and
+// false if there are none.
+func (s *SVG) Bounds() (Box, bool) {
+	if len(s.shapes) == 0 {
+		return Box{}, false
+	}
+
+	points := make([]Point, 0, 2*len(s.shapes))
+	for _, sh := range s.shapes {
+		points = append(points, sh.bounds[:]...)
+	}
+
+	return boundingBox(points), true
+}
+
+// Marshal returns the scene as an SVG image.
+func (s *SVG) Marshal() ([]byte, error) {
+	w := svgWriter{prec: s.Precision}
+
+	width, maxHeight, margin := s.Width, s.Height, s.Margin
+	if width <= 0 {
+		width = svgDefaultWidth
+	}
+	if maxHeight <= 0 {
+		maxHeight = width
+	}
+	if margin <= 0 {
+		margin = svgDefaultMargin
+	}
+	if 2*margin >= width || 2*margin >= maxHeight {
+		margin = 0
+	}
+
+	// the size of the scene, with something to see if it is a single point
+	// or a straight line
+	bounds, _ := s.Bounds()
+	max, min := bounds[0], bounds[1]
+	sw, sh := max.x-min.x, max.y-min.y
+	switch {
+	case sw == 0 && sh == 0:
+		sw, sh = 1, 1
+	case sw == 0:
+		sw = sh
+	case sh == 0:
+		sh = sw
+	}
+	mid := Point{x: (max.x + min.x) / 2, y: (max.y + min.y) / 2}
+
+	// the scene units in a pixel, so that the scene fits both the width
+	// and the height; a tall scene is centered, with space on either side
+	w.unit = math.Max(sw/float64(width-2*margin), sh/float64(maxHeight-2*margin))
+	height := int(math.Ceil(sh/w.unit)) + 2*margin
+	if height > maxHeight {
+		height = maxHeight
+	}
+
+	w.b = append(w.b, `<svg xmlns="http://www.w3.org/2000/svg" width="`...)
+	w.b = strconv.AppendInt(w.b, int64(width), 10)
+	w.b = append(w.b, `" height="`...)
+	w.b = strconv.AppendInt(w.b, int64(height), 10)
+	w.b = append(w.b, `" viewBox="`...)
+	w.float(mid.x - float64(width)*w.unit/2)
+	w.b = append(w.b, ' ')
+	w.float(svgY(mid.y) - float64(height)*w.unit/2)
+	w.b = append(w.b, ' ')
+	w.float(float64(width) * w.unit)
+	w.b = append(w.b, ' ')
+	w.float(float64(height) * w.unit)
+	w.b = append(w.b, "\">\n"...)
+	w.b = append(w.b, "<g fill=\"none\" stroke=\"black\">\n"...)
+
+	for _, sh := range s.shapes {
+		w.shape(sh.shape, sh.style)
+	}
+
+	w.b = append(w.b, "</g>\n</svg>\n"...)
+
+	return w.b, nil
+}
+
+// WriteTo writes the scene to w as an SVG image.
+func (s *SVG) WriteTo(w io.Writer) (int64, error) {
+	b, err := s.Marshal()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// ----------
+
+type svgWriter struct {
+	b    []byte
+	prec Precision
+	unit float64 // scene units in a pixel
+}
+
+// returns the SVG y coordinate of a scene y coordinate, without a negative
+// zero
+func svgY(y float64) float64 {
+	return 0 - y
+}
+
+func (w *svgWriter) float(f float64) {
+	w.b = w.prec.appendFloat(w.b, f)
+}
+
+// writes name="f"
+func (w *svgWriter) attr(name string, f float64) {
+	w.b = append(w.b, ' ')
+	w.b = append(w.b, name...)
+	w.b = append(w.b, '=', '"')
+	w.float(f)
+	w.b = append(w.b, '"')
+}
+
+// writes points="x1,y1 x2,y2 ..."
+func (w *svgWriter) points(points []Point) {
+	w.b = append(w.b, ` points="`...)
+	for i, p := range points {
+		if i > 0 {
+			w.b = append(w.b, ' ')
+		}
+		w.float(p.x)
+		w.b = append(w.b, ',')
+		w.float(svgY(p.y))
+	}
+	w.b = append(w.b, '"')
+}
+
+// writes the style attributes, and any defaults they don't override, in
+// order of name
+func (w *svgWriter) style(style SVGStyle, defaults SVGStyle) {
+	names := make([]string, 0, len(style)+len(defaults))
+	for name := range defaults {
+		if _, ok := style[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for name := range style {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		value, ok := style[name]
+		if !ok {
+			value = defaults[name]
+		}
+
+		buf.Reset()
+		xml.EscapeText(&buf, []byte(value))
+
+		w.b = append(w.b, ' ')
+		w.b = append(w.b, name...)
+		w.b = append(w.b, '=', '"')
+		w.b = append(w.b, buf.Bytes()...)
+		w.b = append(w.b, '"')
+	}
+}
+
+var svgStroke = SVGStyle{"vector-effect": "non-scaling-stroke"}
+var svgFill = SVGStyle{"fill": "black", "stroke": "none"}
+
+func (w *svgWriter) shape(shape interface{}, style SVGStyle) {
+	defaults := svgStroke
+
+	switch g := shape.(type) {
+	case Point:
+		w.b = append(w.b, "<circle"...)
+		w.attr("cx", g.x)
+		w.attr("cy", svgY(g.y))
+		w.attr("r", svgPointRadius*w.unit)
+		defaults = svgFill
+	case Segment:
+		w.b = append(w.b, "<line"...)
+		w.attr("x1", g[0].x)
+		w.attr("y1", svgY(g[0].y))
+		w.attr("x2", g[1].x)
+		w.attr("y2", svgY(g[1].y))
+	case Box:
+		w.b = append(w.b, "<rect"...)
+		w.attr("x", g[1].x)
+		w.attr("y", svgY(g[0].y))
+		w.attr("width", g[0].x-g[1].x)
+		w.attr("height", g[0].y-g[1].y)
+	case Circle:
+		w.b = append(w.b, "<circle"...)
+		w.attr("cx", g.center.x)
+		w.attr("cy", svgY(g.center.y))
+		w.attr("r", g.radius)
+	case Path:
+		if g.closed {
+			w.b = append(w.b, "<polygon"...)
+		} else {
+			w.b = append(w.b, "<polyline"...)
+		}
+		w.points(g.point)
+	case Polygon:
+		w.b = append(w.b, "<polygon"...)
+		w.points(g.point)
+	case svgArrow:
+		w.b = append(w.b, "<polyline"...)
+		w.points(w.arrow(g))
+	}
+
+	w.style(style, defaults)
+	w.b = append(w.b, "/>\n"...)
+}
+
+// returns the points of an arrow, drawn as one line from the tail to the
+// tip and back along one side of the head, across to the other side and
+// back to the tip
+func (w *svgWriter) arrow(a svgArrow) []Point {
+	tip := a.from.Translate(a.v)
+	if a.v.x == 0 && a.v.y == 0 {
+		return []Point{a.from, tip}
+	}
+
+	back := a.v.Unit().Scale(-svgArrowHead * w.unit)
+	side := func(angle float64) Point {
+		sin, cos := math.Sincos(angle)
+		return tip.Translate(Vector{x: back.x*cos - back.y*sin, y: back.x*sin + back.y*cos})
+	}
+
+	return []Point{a.from, tip, side(svgArrowAngle), side(-svgArrowAngle), tip}
+}
@@ -0,0 +1,125 @@
+package geometry
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+
+	Convey("Given a scene of shapes", t, func() {
+		s := SVG{Width: 120, Margin: 10}
+		So(s.Add(NewBox(Point{0, 0}, Point{10, 5}), nil), ShouldBeNil)
+		So(s.Add(NewSegment(Point{0, 0}, Point{10, 5}), SVGStyle{"stroke": "red"}), ShouldBeNil)
+		So(s.Add(NewPoint(10, 5), nil), ShouldBeNil)
+		So(s.Add(NewCircle(Point{2, 2}, 1), SVGStyle{"fill": "blue", "opacity": "0.5"}), ShouldBeNil)
+		So(s.Add(NewPath(Point{0, 0}, Point{5, 5}), nil), ShouldBeNil)
+		So(s.Add(NewClosedPath(Point{0, 0}, Point{5, 5}, Point{5, 0}), nil), ShouldBeNil)
+		So(s.Add(NewPolygon(Point{1, 1}, Point{2, 1}, Point{2, 2}), SVGStyle{"class": `a"b`}), ShouldBeNil)
+
+		b, err := s.Marshal()
+		So(err, ShouldBeNil)
+		lines := strings.Split(string(b), "\n")
+
+		Convey("The viewBox should fit the bounds, with the y axis flipped", func() {
+			// 10 units across 100 pixels, and 1 unit of margin all round
+			So(lines[0], ShouldEqual, `<svg xmlns="http://www.w3.org/2000/svg" width="120" height="70" viewBox="-1 -6 12 7">`)
+
+			bounds, ok := s.Bounds()
+			So(ok, ShouldBeTrue)
+			So(bounds, ShouldResemble, NewBox(Point{0, 0}, Point{10, 5}))
+		})
+
+		Convey("Each shape should be drawn with its style over the defaults", func() {
+			So(lines[1:], ShouldResemble, []string{
+				`<g fill="none" stroke="black">`,
+				`<rect x="0" y="-5" width="10" height="5" vector-effect="non-scaling-stroke"/>`,
+				`<line x1="0" y1="0" x2="10" y2="-5" stroke="red" vector-effect="non-scaling-stroke"/>`,
+				`<circle cx="10" cy="-5" r="0.30000000000000004" fill="black" stroke="none"/>`,
+				`<circle cx="2" cy="-2" r="1" fill="blue" opacity="0.5" vector-effect="non-scaling-stroke"/>`,
+				`<polyline points="0,0 5,-5" vector-effect="non-scaling-stroke"/>`,
+				`<polygon points="0,0 5,-5 5,0" vector-effect="non-scaling-stroke"/>`,
+				`<polygon points="1,-1 2,-1 2,-2" class="a&#34;b" vector-effect="non-scaling-stroke"/>`,
+				`</g>`,
+				`</svg>`,
+				``,
+			})
+		})
+
+		Convey("The precision should round the coordinates", func() {
+			s.Precision = Decimals(2)
+			b, err := s.Marshal()
+			So(err, ShouldBeNil)
+			So(string(b), ShouldContainSubstring, `<circle cx="10" cy="-5" r="0.3" fill="black" stroke="none"/>`)
+		})
+
+		Convey("WriteTo should write the same image", func() {
+			var buf bytes.Buffer
+			n, err := s.WriteTo(&buf)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, len(b))
+			So(buf.Bytes(), ShouldResemble, b)
+		})
+	})
+
+	Convey("Given vectors", t, func() {
+		s := SVG{Width: 100, Precision: Decimals(3)}
+		So(s.Add(NewVector(10, 0), nil), ShouldBeNil)
+		So(s.AddVector(Point{0, 10}, NewVector(0, -10), SVGStyle{"stroke": "green"}), ShouldBeNil)
+
+		b, err := s.Marshal()
+		So(err, ShouldBeNil)
+
+		Convey("They should be drawn as arrows from their points", func() {
+			// sides of 8 pixels, at 10 units across 80 pixels
+			So(string(b), ShouldContainSubstring, `<polyline points="0,0 10,0 9.076,0.383 9.076,-0.383 10,0" vector-effect="non-scaling-stroke"/>`)
+			So(string(b), ShouldContainSubstring, `<polyline points="0,-10 0,0 -0.383,-0.924 0.383,-0.924 0,0" stroke="green" vector-effect="non-scaling-stroke"/>`)
+		})
+	})
+
+	Convey("Given degenerate scenes", t, func() {
+
+		Convey("An empty scene should still be drawn", func() {
+			b, err := (&SVG{}).Marshal()
+			So(err, ShouldBeNil)
+			So(string(b), ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" `)
+		})
+
+		Convey("A horizontal line should be drawn in a square", func() {
+			s := SVG{}
+			So(s.Add(NewSegment(Point{0, 3}, Point{10, 3}), nil), ShouldBeNil)
+			b, err := s.Marshal()
+			So(err, ShouldBeNil)
+			So(string(b), ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" `)
+		})
+
+		Convey("A near vertical line should be fitted to the height", func() {
+			s := SVG{Width: 200, Height: 300, Precision: Decimals(3)}
+			So(s.Add(NewSegment(Point{0, 0}, Point{1e-9, 1000}), nil), ShouldBeNil)
+			b, err := s.Marshal()
+			So(err, ShouldBeNil)
+			So(string(b), ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="300" viewBox="-357.143 -1035.714 714.286 1071.429">`)
+
+			s.Height = 0
+			b, err = s.Marshal()
+			So(err, ShouldBeNil)
+			So(string(b), ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" `)
+		})
+	})
+
+	Convey("Given shapes which cannot be drawn", t, func() {
+		var s SVG
+
+		So(s.Add(NewLineCoefficients(1, 1, 0), nil), ShouldNotBeNil)
+		So(errors.Is(s.Add(NewPoint(math.NaN(), 0), nil), ErrInvalidGeometry), ShouldBeTrue)
+		So(errors.Is(s.Add(Path{}, nil), ErrInvalidGeometry), ShouldBeTrue)
+		So(errors.Is(s.AddVector(Origin, NewVector(math.Inf(1), 0), nil), ErrInvalidGeometry), ShouldBeTrue)
+
+		_, ok := s.Bounds()
+		So(ok, ShouldBeFalse)
+	})
+}
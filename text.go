@@ -0,0 +1,755 @@
+package geometry
+
+// Parsers and encoders for the postgres text representation of the geometric
+// types.  The parsers follow the input functions in postgres'
+// src/backend/utils/adt/geo_ops.c closely, so anything the server will accept
+// is accepted here too, including all the optional parentheses.  The encoders
+// write the same text as the server's output functions.
+
+import (
+	"encoding"
+	"strconv"
+	"strings"
+)
+
+const (
+	ldelim   = '('
+	rdelim   = ')'
+	delim    = ','
+	ldelimEP = '['
+	rdelimEP = ']'
+	ldelimC  = '<'
+	rdelimC  = '>'
+	ldelimL  = '{'
+	rdelimL  = '}'
+)
+
+// whitespace as recognized by C's isspace()
+const space = " \t\n\v\f\r"
+
+// textDecoder walks through a single postgres geometric literal.
+type textDecoder struct {
+	typ string // postgres type name, used in errors
+	in  string // the complete input
+	s   string // the input not yet consumed
+}
+
+func newTextDecoder(typ, in string) *textDecoder {
+	return &textDecoder{typ: typ, in: in, s: in}
+}
+
+func (d *textDecoder) fail() error {
+	return &SyntaxError{Type: d.typ, Input: d.in}
+}
+
+func (d *textDecoder) skipSpace() {
+	d.s = strings.TrimLeft(d.s, space)
+}
+
+func (d *textDecoder) peek() byte {
+	if len(d.s) == 0 {
+		return 0
+	}
+	return d.s[0]
+}
+
+// accept consumes c if it is the next byte of input.
+func (d *textDecoder) accept(c byte) bool {
+	if d.peek() != c {
+		return false
+	}
+	d.s = d.s[1:]
+	return true
+}
+
+// end ensures that all input has been consumed.
+func (d *textDecoder) end() error {
+	if d.s != "" {
+		return d.fail()
+	}
+	return nil
+}
+
+// single reads one float and any whitespace surrounding it.
+func (d *textDecoder) single() (float64, error) {
+	d.skipSpace()
+
+	n := strings.IndexAny(d.s, "()[]<>{},"+space)
+	if n < 0 {
+		n = len(d.s)
+	}
+
+	f, err := strconv.ParseFloat(d.s[:n], 64)
+	if err != nil {
+		return 0, d.fail()
+	}
+
+	d.s = d.s[n:]
+	d.skipSpace()
+	return f, nil
+}
+
+// pair reads an x,y pair, optionally enclosed in parentheses.
+func (d *textDecoder) pair() (x, y float64, err error) {
+	d.skipSpace()
+	hasDelim := d.accept(ldelim)
+
+	if x, err = d.single(); err != nil {
+		return 0, 0, err
+	}
+
+	if !d.accept(delim) {
+		return 0, 0, d.fail()
+	}
+
+	if y, err = d.single(); err != nil {
+		return 0, 0, err
+	}
+
+	if hasDelim {
+		if !d.accept(rdelim) {
+			return 0, 0, d.fail()
+		}
+		d.skipSpace()
+	}
+
+	return x, y, nil
+}
+
+// points reads npts pairs, with the optional enclosing delimiters allowed
+// for an lseg, box, path or polygon.  The [] delimiters of an open path are
+// only permitted when opentype is true.
+func (d *textDecoder) points(opentype bool, npts int) (floats []float64, isopen bool, err error) {
+	depth := 0
+
+	d.skipSpace()
+	switch d.peek() {
+	case ldelimEP:
+		if !opentype {
+			return nil, false, d.fail()
+		}
+		isopen = true
+		depth++
+		d.s = d.s[1:]
+	case ldelim:
+		cp := strings.TrimLeft(d.s[1:], space)
+		// a doubled paren, or a single paren around bare numbers
+		if (cp != "" && cp[0] == ldelim) || strings.LastIndexByte(d.s, ldelim) == 0 {
+			depth++
+			d.s = cp
+		}
+	}
+
+	floats = make([]float64, 0, 2*npts)
+
+	for i := 0; i < npts; i++ {
+		x, y, err := d.pair()
+		if err != nil {
+			return nil, false, err
+		}
+		floats = append(floats, x, y)
+		d.accept(delim)
+	}
+
+	for depth > 0 {
+		c := d.peek()
+		if c != rdelim && !(c == rdelimEP && isopen && depth == 1) {
+			return nil, false, d.fail()
+		}
+		depth--
+		d.s = d.s[1:]
+		d.skipSpace()
+	}
+
+	return floats, isopen, nil
+}
+
+// decodePoint parses a point, in any of the forms
+//
+//	( x , y )
+//	  x , y
+func decodePoint(s string) ([]float64, error) {
+	d := newTextDecoder("point", s)
+
+	x, y, err := d.pair()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+
+	return []float64{x, y}, nil
+}
+
+// decodeSegment parses an lseg, in any of the forms
+//
+//	[ ( x1 , y1 ) , ( x2 , y2 ) ]
+//	( ( x1 , y1 ) , ( x2 , y2 ) )
+//	  ( x1 , y1 ) , ( x2 , y2 )
+//	    x1 , y1   ,   x2 , y2
+func decodeSegment(s string) ([]float64, error) {
+	d := newTextDecoder("lseg", s)
+
+	floats, _, err := d.points(true, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+
+	return floats, nil
+}
+
+// decodeBox parses a box, in any of the forms
+//
+//	( ( x1 , y1 ) , ( x2 , y2 ) )
+//	  ( x1 , y1 ) , ( x2 , y2 )
+//	    x1 , y1   ,   x2 , y2
+func decodeBox(s string) ([]float64, error) {
+	d := newTextDecoder("box", s)
+
+	floats, _, err := d.points(false, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+
+	return floats, nil
+}
+
+// decodeCircle parses a circle, in any of the forms
+//
+//	< ( x , y ) , r >
+//	( ( x , y ) , r )
+//	  ( x , y ) , r
+//	    x , y   , r
+func decodeCircle(s string) ([]float64, error) {
+	d := newTextDecoder("circle", s)
+	depth := 0
+
+	d.skipSpace()
+	switch d.peek() {
+	case ldelimC:
+		depth++
+		d.s = d.s[1:]
+	case ldelim:
+		// if there are two left parens, consume the first one
+		cp := strings.TrimLeft(d.s[1:], space)
+		if cp != "" && cp[0] == ldelim {
+			depth++
+			d.s = cp
+		}
+	}
+
+	x, y, err := d.pair()
+	if err != nil {
+		return nil, err
+	}
+
+	d.accept(delim)
+
+	r, err := d.single()
+	if err != nil {
+		return nil, err
+	}
+
+	if r < 0 {
+		return nil, &GeometryError{Type: "circle", Reason: "radius cannot be negative"}
+	}
+
+	for depth > 0 {
+		c := d.peek()
+		if c != rdelim && !(c == rdelimC && depth == 1) {
+			return nil, d.fail()
+		}
+		depth--
+		d.s = d.s[1:]
+		d.skipSpace()
+	}
+
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+
+	return []float64{x, y, r}, nil
+}
+
+// counts the pairs in a list of points, or returns -1 if the delimiters
+// don't add up to a whole number of pairs
+func pairCount(s string) int {
+	ndelim := strings.Count(s, string(delim))
+	if ndelim%2 == 0 {
+		return -1
+	}
+	return (ndelim + 1) / 2
+}
+
+// decodePath parses a path, and whether it is closed, from any of the forms
+//
+//	[ ( x1 , y1 ) , ... , ( xn , yn ) ]
+//	( ( x1 , y1 ) , ... , ( xn , yn ) )
+//	  ( x1 , y1 ) , ... , ( xn , yn )
+//	  ( x1 , y1   , ... ,   xn , yn )
+//	    x1 , y1   , ... ,   xn , yn
+//
+// Only the first, with square brackets, is an open path.
+func decodePath(s string) (floats []float64, closed bool, err error) {
+	d := newTextDecoder("path", s)
+
+	npts := pairCount(s)
+	if npts <= 0 {
+		return nil, false, d.fail()
+	}
+
+	// skip a single leading paren
+	depth := 0
+	d.skipSpace()
+	if d.peek() == ldelim && strings.LastIndexByte(d.s, ldelim) == 0 {
+		depth++
+		d.s = d.s[1:]
+	}
+
+	floats, isopen, err := d.points(true, npts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if depth > 0 {
+		if !d.accept(rdelim) {
+			return nil, false, d.fail()
+		}
+		d.skipSpace()
+	}
+
+	if err := d.end(); err != nil {
+		return nil, false, err
+	}
+
+	return floats, !isopen, nil
+}
+
+// decodePolygon parses a polygon, in any of the forms
+//
+//	( ( x1 , y1 ) , ... , ( xn , yn ) )
+//	  ( x1 , y1 ) , ... , ( xn , yn )
+//	  ( x1 , y1   , ... ,   xn , yn )
+//	    x1 , y1   , ... ,   xn , yn
+func decodePolygon(s string) ([]float64, error) {
+	d := newTextDecoder("polygon", s)
+
+	npts := pairCount(s)
+	if npts <= 0 {
+		return nil, d.fail()
+	}
+
+	floats, _, err := d.points(false, npts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+
+	return floats, nil
+}
+
+// decodeLine parses the coefficients of a line, from either the form
+//
+//	{ A , B , C }
+//
+// or the coordinates of two distinct points on the line, in any of the forms
+// accepted for an lseg.
+func decodeLine(s string) ([]float64, error) {
+	d := newTextDecoder("line", s)
+
+	d.skipSpace()
+	if !d.accept(ldelimL) {
+		floats, err := decodeSegment(s)
+		if err != nil {
+			return nil, d.fail()
+		}
+
+		p1 := Point{x: floats[0], y: floats[1]}
+		p2 := Point{x: floats[2], y: floats[3]}
+		if p1.Same(p2) {
+			return nil, &GeometryError{Type: "line", Reason: "must be two distinct points"}
+		}
+
+		a, b, c := NewLine(p1, p2).Values()
+		return []float64{a, b, c}, nil
+	}
+
+	floats := make([]float64, 3)
+
+	for i := range floats {
+		f, err := d.single()
+		if err != nil {
+			return nil, err
+		}
+		floats[i] = f
+
+		if i < 2 && !d.accept(delim) {
+			return nil, d.fail()
+		}
+	}
+
+	if !d.accept(rdelimL) {
+		return nil, d.fail()
+	}
+
+	d.skipSpace()
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+
+	if fpZero(floats[0]) && fpZero(floats[1]) {
+		return nil, &GeometryError{Type: "line", Reason: "A and B cannot both be zero"}
+	}
+
+	return floats, nil
+}
+
+// ----------
+
+// ParsePoint parses a point, as "(x,y)" or "x,y".
+func ParsePoint(s string) (Point, error) {
+	floats, err := decodePoint(s)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{x: floats[0], y: floats[1]}, nil
+}
+
+// ParseSegment parses an lseg, as "[(x1,y1),(x2,y2)]" or any of the other
+// forms postgres accepts.
+func ParseSegment(s string) (Segment, error) {
+	floats, err := decodeSegment(s)
+	if err != nil {
+		return Segment{}, err
+	}
+	return Segment{{x: floats[0], y: floats[1]}, {x: floats[2], y: floats[3]}}, nil
+}
+
+// ParseBox parses a box, as "((x1,y1),(x2,y2))" or any of the other forms
+// postgres accepts.  The corners may be in any order, and are normalized as
+// NewBox does.
+func ParseBox(s string) (Box, error) {
+	floats, err := decodeBox(s)
+	if err != nil {
+		return Box{}, err
+	}
+	return NewBox(Point{x: floats[0], y: floats[1]}, Point{x: floats[2], y: floats[3]}), nil
+}
+
+// ParseCircle parses a circle, as "<(x,y),r>" or any of the other forms
+// postgres accepts.
+func ParseCircle(s string) (Circle, error) {
+	floats, err := decodeCircle(s)
+	if err != nil {
+		return Circle{}, err
+	}
+	return Circle{center: Point{x: floats[0], y: floats[1]}, radius: floats[2]}, nil
+}
+
+// ParsePath parses a path, as "[(x1,y1),...]" for an open path or
+// "((x1,y1),...)" for a closed one, or any of the other forms postgres
+// accepts, all of which are closed.
+func ParsePath(s string) (Path, error) {
+	floats, closed, err := decodePath(s)
+	if err != nil {
+		return Path{}, err
+	}
+	return Path{point: floatPoints(floats), closed: closed}, nil
+}
+
+// ParsePolygon parses a polygon, as "((x1,y1),...)" or any of the other
+// forms postgres accepts.
+func ParsePolygon(s string) (Polygon, error) {
+	floats, err := decodePolygon(s)
+	if err != nil {
+		return Polygon{}, err
+	}
+	return Polygon{point: floatPoints(floats)}, nil
+}
+
+// ParseLine parses a line, as its coefficients "{A,B,C}", or as two distinct
+// points on the line in any of the forms accepted for an lseg.
+func ParseLine(s string) (Line, error) {
+	floats, err := decodeLine(s)
+	if err != nil {
+		return Line{}, err
+	}
+	return Line{a: floats[0], b: floats[1], c: floats[2]}, nil
+}
+
+// returns the points of a list of coordinates
+func floatPoints(floats []float64) []Point {
+	points := make([]Point, len(floats)/2)
+	for i := range points {
+		points[i].x = floats[2*i]
+		points[i].y = floats[2*i+1]
+	}
+	return points
+}
+
+// assert that types implement encoding.TextMarshaler and TextUnmarshaler,
+// for use as JSON map keys, flags and the like
+var _ encoding.TextMarshaler = Point{}
+var _ encoding.TextMarshaler = Vector{}
+var _ encoding.TextMarshaler = Segment{}
+var _ encoding.TextMarshaler = Box{}
+var _ encoding.TextMarshaler = Circle{}
+var _ encoding.TextMarshaler = Path{}
+var _ encoding.TextMarshaler = Polygon{}
+var _ encoding.TextMarshaler = Line{}
+var _ encoding.TextUnmarshaler = &Point{}
+var _ encoding.TextUnmarshaler = &Vector{}
+var _ encoding.TextUnmarshaler = &Segment{}
+var _ encoding.TextUnmarshaler = &Box{}
+var _ encoding.TextUnmarshaler = &Circle{}
+var _ encoding.TextUnmarshaler = &Path{}
+var _ encoding.TextUnmarshaler = &Polygon{}
+var _ encoding.TextUnmarshaler = &Line{}
+
+// appends a point as (x,y)
+func appendTextPoint(b []byte, p Point, prec Precision) []byte {
+	b = append(b, '(')
+	b = prec.appendFloat(b, p.x)
+	b = append(b, ',')
+	b = prec.appendFloat(b, p.y)
+	return append(b, ')')
+}
+
+// appends a list of points as (x1,y1),...,(xn,yn)
+func appendTextPoints(b []byte, points []Point, prec Precision) []byte {
+	for i, pt := range points {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendTextPoint(b, pt, prec)
+	}
+	return b
+}
+
+// The appendText methods append the text of a value with its coordinates
+// written to the precision.  MarshalText, and so Value, always write them
+// Lossless.
+
+// ----------
+
+func (p Point) appendText(b []byte, prec Precision) []byte {
+	return appendTextPoint(b, p, prec)
+}
+
+// Implements encoding.TextMarshaler interface, as (x,y)
+func (p Point) MarshalText() ([]byte, error) {
+	return p.appendText(make([]byte, 0, 10), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (p *Point) UnmarshalText(text []byte) error {
+	r, err := ParsePoint(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = r
+	return nil
+}
+
+// ----------
+
+func (v Vector) appendText(b []byte, prec Precision) []byte {
+	return appendTextPoint(b, Point(v), prec)
+}
+
+// Implements encoding.TextMarshaler interface, as (x,y)
+func (v Vector) MarshalText() ([]byte, error) {
+	return v.appendText(make([]byte, 0, 10), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (v *Vector) UnmarshalText(text []byte) error {
+	return (*Point)(v).UnmarshalText(text)
+}
+
+// ----------
+
+func (s Segment) appendText(b []byte, prec Precision) []byte {
+	b = append(b, '[')
+	b = appendTextPoints(b, s[:], prec)
+	return append(b, ']')
+}
+
+// Implements encoding.TextMarshaler interface, as [(x1,y1),(x2,y2)]
+func (s Segment) MarshalText() ([]byte, error) {
+	return s.appendText(make([]byte, 0, 20), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (s *Segment) UnmarshalText(text []byte) error {
+	r, err := ParseSegment(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = r
+	return nil
+}
+
+// ----------
+
+func (b Box) appendText(by []byte, prec Precision) []byte {
+	by = append(by, '(')
+	by = appendTextPoints(by, b[:], prec)
+	return append(by, ')')
+}
+
+// Implements encoding.TextMarshaler interface, as ((x1,y1),(x2,y2))
+func (b Box) MarshalText() ([]byte, error) {
+	return b.appendText(make([]byte, 0, 20), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface.  The corners may be in any
+// order, and are normalized as NewBox does.
+func (b *Box) UnmarshalText(text []byte) error {
+	r, err := ParseBox(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = r
+	return nil
+}
+
+// ----------
+
+func (c Circle) appendText(b []byte, prec Precision) []byte {
+	b = append(b, '<')
+	b = appendTextPoint(b, c.center, prec)
+	b = append(b, ',')
+	b = prec.appendFloat(b, c.radius)
+	return append(b, '>')
+}
+
+// Implements encoding.TextMarshaler interface, as <(x,y),r>
+func (c Circle) MarshalText() ([]byte, error) {
+	return c.appendText(make([]byte, 0, 16), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (c *Circle) UnmarshalText(text []byte) error {
+	r, err := ParseCircle(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = r
+	return nil
+}
+
+// ----------
+
+func (p Path) appendText(b []byte, prec Precision) []byte {
+	if p.closed {
+		b = append(b, '(')
+	} else {
+		b = append(b, '[')
+	}
+
+	b = appendTextPoints(b, p.point, prec)
+
+	if p.closed {
+		b = append(b, ')')
+	} else {
+		b = append(b, ']')
+	}
+
+	return b
+}
+
+// Implements encoding.TextMarshaler interface, as [(x1,y1),...] if the path
+// is open, or ((x1,y1),...) if it is closed.  A path must have points.
+func (p Path) MarshalText() ([]byte, error) {
+	if len(p.point) == 0 {
+		return nil, &GeometryError{Type: "path", Reason: "cannot encode a path with no points"}
+	}
+
+	return p.appendText(make([]byte, 0, 10*len(p.point)), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (p *Path) UnmarshalText(text []byte) error {
+	r, err := ParsePath(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = r
+	return nil
+}
+
+// ----------
+
+func (p Polygon) appendText(b []byte, prec Precision) []byte {
+	b = append(b, '(')
+	b = appendTextPoints(b, p.point, prec)
+	return append(b, ')')
+}
+
+// Implements encoding.TextMarshaler interface, as ((x1,y1),...).  A polygon
+// must have points.
+func (p Polygon) MarshalText() ([]byte, error) {
+	if len(p.point) == 0 {
+		return nil, &GeometryError{Type: "polygon", Reason: "cannot encode a polygon with no points"}
+	}
+
+	return p.appendText(make([]byte, 0, 10*len(p.point)), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (p *Polygon) UnmarshalText(text []byte) error {
+	r, err := ParsePolygon(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = r
+	return nil
+}
+
+// ----------
+
+func (l Line) appendText(b []byte, prec Precision) []byte {
+	b = append(b, '{')
+	b = prec.appendFloat(b, l.a)
+	b = append(b, ',')
+	b = prec.appendFloat(b, l.b)
+	b = append(b, ',')
+	b = prec.appendFloat(b, l.c)
+	return append(b, '}')
+}
+
+// Implements encoding.TextMarshaler interface, as {A,B,C}
+func (l Line) MarshalText() ([]byte, error) {
+	return l.appendText(make([]byte, 0, 10), Precision{}), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (l *Line) UnmarshalText(text []byte) error {
+	r, err := ParseLine(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = r
+	return nil
+}
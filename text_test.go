@@ -0,0 +1,298 @@
+package geometry
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeText(t *testing.T) {
+
+	Convey("Given postgres text for a point", t, func() {
+
+		Convey("All accepted forms should decode", func() {
+			for _, s := range []string{"(1,2)", "1,2", " ( 1 , 2 ) ", "(1e0,2.0)", "\t1,\n2 "} {
+				f, err := decodePoint(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, 2})
+			}
+		})
+
+		Convey("Special values should decode", func() {
+			f, err := decodePoint("(-Infinity,NaN)")
+			So(err, ShouldBeNil)
+			So(math.IsInf(f[0], -1), ShouldBeTrue)
+			So(math.IsNaN(f[1]), ShouldBeTrue)
+		})
+
+		Convey("Malformed input should be rejected", func() {
+			for _, s := range []string{"", "(1,2", "1,2)", "(1 2)", "(1,2,3)", "((1,2))", "(a,b)", "(1,2)x"} {
+				f, err := decodePoint(s)
+				So(f, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given postgres text for an lseg", t, func() {
+
+		Convey("All accepted forms should decode", func() {
+			for _, s := range []string{"[(1,2),(3,4)]", "((1,2),(3,4))", "(1,2),(3,4)", "1,2,3,4", "(1,2,3,4)", "[1,2,3,4]", " [ ( 1 , 2 ) , ( 3 , 4 ) ] "} {
+				f, err := decodeSegment(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, 2, 3, 4})
+			}
+		})
+
+		Convey("Malformed input should be rejected", func() {
+			for _, s := range []string{"((1,2),(3,4)]", "[(1,2)]", "(1,2),(3,4),(5,6)", "[(1,2),(3,4)"} {
+				_, err := decodeSegment(s)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given postgres text for a box", t, func() {
+
+		Convey("All accepted forms should decode", func() {
+			for _, s := range []string{"((3,4),(1,2))", "(3,4),(1,2)", "3,4,1,2", "(3,4,1,2)"} {
+				f, err := decodeBox(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{3, 4, 1, 2})
+			}
+		})
+
+		Convey("The open path syntax should be rejected", func() {
+			_, err := decodeBox("[(3,4),(1,2)]")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given postgres text for a circle", t, func() {
+
+		Convey("All accepted forms should decode", func() {
+			for _, s := range []string{"<(1,2),3>", "((1,2),3)", "(1,2),3", "1,2,3", "<1,2,3>", " < ( 1 , 2 ) , 3 > "} {
+				f, err := decodeCircle(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, 2, 3})
+			}
+		})
+
+		Convey("Malformed input should be rejected", func() {
+			for _, s := range []string{"<(1,2),-3>", "<(1,2)>", "(1,2,3)", "<(1,2),3>>"} {
+				_, err := decodeCircle(s)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given postgres text for a path", t, func() {
+
+		Convey("The open form should decode as open", func() {
+			f, closed, err := decodePath("[(1,2),(3,4),(5,6)]")
+			So(err, ShouldBeNil)
+			So(closed, ShouldBeFalse)
+			So(f, ShouldResemble, []float64{1, 2, 3, 4, 5, 6})
+		})
+
+		Convey("All other forms should decode as closed", func() {
+			for _, s := range []string{"((1,2),(3,4),(5,6))", "(1,2),(3,4),(5,6)", "(1,2,3,4,5,6)", "1,2,3,4,5,6", " ( ( 1 , 2 ) , ( 3 , 4 ) , ( 5 , 6 ) ) "} {
+				f, closed, err := decodePath(s)
+				So(err, ShouldBeNil)
+				So(closed, ShouldBeTrue)
+				So(f, ShouldResemble, []float64{1, 2, 3, 4, 5, 6})
+			}
+		})
+
+		Convey("A single point should decode", func() {
+			for _, s := range []string{"[(1,2)]", "(1,2)", "1,2"} {
+				f, _, err := decodePath(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, 2})
+			}
+		})
+
+		Convey("A single point nested in parens should decode as closed, as postgres writes it", func() {
+			for _, s := range []string{"((10,20))", " ( ( 10 , 20 ) ) "} {
+				f, closed, err := decodePath(s)
+				So(err, ShouldBeNil)
+				So(closed, ShouldBeTrue)
+				So(f, ShouldResemble, []float64{10, 20})
+			}
+
+			p := NewClosedPath(Point{10, 20})
+			text, err := p.MarshalText()
+			So(err, ShouldBeNil)
+			So(string(text), ShouldEqual, "((10,20))")
+
+			var scanned Path
+			So(scanned.Scan(text), ShouldBeNil)
+			So(scanned, ShouldResemble, p)
+		})
+
+		Convey("Malformed input should be rejected", func() {
+			for _, s := range []string{"", "[]", "(1,2,3)", "((1,2),(3,4)]", "[(1,2),(3,4)]x"} {
+				_, _, err := decodePath(s)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given postgres text for a polygon", t, func() {
+
+		Convey("All accepted forms should decode", func() {
+			for _, s := range []string{"((1,2),(3,4),(5,6))", "(1,2),(3,4),(5,6)", "(1,2,3,4,5,6)", "1,2,3,4,5,6"} {
+				f, err := decodePolygon(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, 2, 3, 4, 5, 6})
+			}
+		})
+
+		Convey("The open path syntax should be rejected", func() {
+			_, err := decodePolygon("[(1,2),(3,4),(5,6)]")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given postgres text for a line", t, func() {
+
+		Convey("The coefficient form should decode", func() {
+			for _, s := range []string{"{1,-1,0.5}", " { 1 , -1 , 0.5 } "} {
+				f, err := decodeLine(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, -1, 0.5})
+			}
+		})
+
+		Convey("Two points should decode as the line through them", func() {
+			for _, s := range []string{"[(0,0),(1,1)]", "((0,0),(1,1))", "0,0,1,1"} {
+				f, err := decodeLine(s)
+				So(err, ShouldBeNil)
+				So(f, ShouldResemble, []float64{1, -1, 0})
+			}
+		})
+
+		Convey("Malformed input should be rejected", func() {
+			for _, s := range []string{"{0,0,1}", "{1e-7,-1e-7,1}", "{1,2}", "{1,2,3", "{1,2,3}x", "[(1,1),(1,1)]", "(1,1)"} {
+				_, err := decodeLine(s)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestTextMarshaling(t *testing.T) {
+
+	Convey("Given values of every type", t, func() {
+		tests := []struct {
+			value encoding.TextMarshaler
+			text  string
+		}{
+			{NewPoint(1, 2.5), "(1,2.5)"},
+			{NewVector(-1, 0), "(-1,0)"},
+			{NewSegment(Point{1, 2}, Point{3, 4}), "[(1,2),(3,4)]"},
+			{NewBox(Point{1, 2}, Point{3, 4}), "((3,4),(1,2))"},
+			{NewCircle(Point{1, 2}, 3), "<(1,2),3>"},
+			{NewPath(Point{0, 0}, Point{1, 1}), "[(0,0),(1,1)]"},
+			{NewClosedPath(Point{0, 0}, Point{1, 1}, Point{2, 0}), "((0,0),(1,1),(2,0))"},
+			{NewPolygon(Point{0, 0}, Point{1, 1}, Point{2, 0}), "((0,0),(1,1),(2,0))"},
+			{NewLineCoefficients(1, -1, 0.5), "{1,-1,0.5}"},
+		}
+
+		Convey("They should be written as Value writes them, and read back", func() {
+			for _, test := range tests {
+				b, err := test.value.MarshalText()
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, test.text)
+
+				v, err := test.value.(driver.Valuer).Value()
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, b)
+
+				r := reflect.New(reflect.TypeOf(test.value))
+				So(r.Interface().(encoding.TextUnmarshaler).UnmarshalText(b), ShouldBeNil)
+				So(r.Elem().Interface(), ShouldResemble, test.value)
+			}
+		})
+
+		Convey("Empty paths and polygons should not be written", func() {
+			_, err := Path{}.MarshalText()
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			_, err = Polygon{}.MarshalText()
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+
+		Convey("They should be usable as JSON map keys", func() {
+			m := map[Point]string{NewPoint(1, 2): "a"}
+			b, err := json.Marshal(m)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"(1,2)":"a"}`)
+
+			var r map[Point]string
+			So(json.Unmarshal(b, &r), ShouldBeNil)
+			So(r, ShouldResemble, m)
+		})
+
+		Convey("They should be usable as flags", func() {
+			var c Circle
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.TextVar(&c, "area", NewCircle(Origin, 1), "")
+
+			So(fs.Parse([]string{"-area", "<(1,2),3>"}), ShouldBeNil)
+			So(c, ShouldResemble, NewCircle(Point{1, 2}, 3))
+		})
+	})
+
+	Convey("Given every spelling postgres accepts", t, func() {
+
+		Convey("The Parse functions should accept them", func() {
+			p, err := ParsePoint(" 1 , 2 ")
+			So(err, ShouldBeNil)
+			So(p, ShouldResemble, NewPoint(1, 2))
+
+			s, err := ParseSegment("1,2,3,4")
+			So(err, ShouldBeNil)
+			So(s, ShouldResemble, NewSegment(Point{1, 2}, Point{3, 4}))
+
+			b, err := ParseBox("(1,2),(3,4)")
+			So(err, ShouldBeNil)
+			So(b, ShouldResemble, NewBox(Point{1, 2}, Point{3, 4}))
+
+			c, err := ParseCircle("((1,2),3)")
+			So(err, ShouldBeNil)
+			So(c, ShouldResemble, NewCircle(Point{1, 2}, 3))
+
+			pa, err := ParsePath("(0,0,1,1)")
+			So(err, ShouldBeNil)
+			So(pa, ShouldResemble, NewClosedPath(Point{0, 0}, Point{1, 1}))
+
+			pg, err := ParsePolygon("0,0,1,1,2,0")
+			So(err, ShouldBeNil)
+			So(pg, ShouldResemble, NewPolygon(Point{0, 0}, Point{1, 1}, Point{2, 0}))
+
+			l, err := ParseLine("[(0,0),(1,1)]")
+			So(err, ShouldBeNil)
+			So(l, ShouldResemble, NewLineCoefficients(1, -1, 0))
+		})
+
+		Convey("Malformed text should return a syntax error", func() {
+			_, err := ParsePoint("(1,2")
+			var se *SyntaxError
+			So(errors.As(err, &se), ShouldBeTrue)
+			So(se.Type, ShouldEqual, "point")
+
+			var b Box
+			So(b.UnmarshalText([]byte("((1,2))")), ShouldNotBeNil)
+
+			_, err = ParseCircle("<(0,0),-1>")
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+		})
+	})
+}
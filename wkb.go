@@ -0,0 +1,449 @@
+package geometry
+
+// Well-Known Binary, the binary format of the OGC simple features spec, in
+// both its ISO flavor and the extended flavor of PostGIS (EWKB), which adds
+// an SRID.  The flavors differ in how they flag Z and M coordinates: ISO
+// adds 1000, 2000 or 3000 to the geometry type, and EWKB sets its high bits.
+// Only two dimensional geometries are supported.
+// info from postgis' doc/ZMSGeoms.txt, and the OGC simple features spec
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+)
+
+// WKB geometry types
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// EWKB flags, in the high bits of the geometry type
+const (
+	ewkbZ     = 0x80000000
+	ewkbM     = 0x40000000
+	ewkbSRID  = 0x20000000
+	ewkbFlags = ewkbZ | ewkbM | ewkbSRID
+)
+
+// WKBFlavor is a flavor of WKB.
+type WKBFlavor int
+
+const (
+	WKBISO      WKBFlavor = iota // the OGC and ISO standard, without an SRID
+	WKBExtended                  // PostGIS' EWKB, with an SRID
+)
+
+// WKBEncoder writes geometries as WKB.  WKB has no circles, so circles are
+// written as polygons of CircleSegments points around the circle, or are an
+// error if CircleSegments is less than 3.
+type WKBEncoder struct {
+	BigEndian      bool // write big endian (XDR), rather than little endian (NDR)
+	Flavor         WKBFlavor
+	SRID           int // the spatial reference system, written only if not zero
+	CircleSegments int
+}
+
+// MarshalWKB returns the little endian ISO WKB of a geometry, which may be
+// any that MarshalWKT accepts.  An empty Point is written with NaN
+// coordinates, as PostGIS does.
+func MarshalWKB(g interface{}) ([]byte, error) {
+	return WKBEncoder{}.Marshal(g)
+}
+
+// Marshal returns the WKB of a geometry, as MarshalWKB does, but with the
+// encoder's byte order and flavor, and also of a Circle if CircleSegments
+// is set.
+func (e WKBEncoder) Marshal(g interface{}) ([]byte, error) {
+	if e.SRID != 0 && e.Flavor != WKBExtended {
+		return nil, fmt.Errorf("Only extended WKB has an SRID, but SRID is %d", e.SRID)
+	}
+
+	return e.append(make([]byte, 0, 64), g, true)
+}
+
+func (e WKBEncoder) order() binary.AppendByteOrder {
+	if e.BigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// Appends the byte order and type of a geometry, and the SRID if it is the
+// outermost one.
+func (e WKBEncoder) appendHeader(b []byte, typ uint32, top bool) []byte {
+	srid := top && e.SRID != 0
+	if srid {
+		typ |= ewkbSRID
+	}
+
+	if e.BigEndian {
+		b = append(b, 0)
+	} else {
+		b = append(b, 1)
+	}
+	b = e.order().AppendUint32(b, typ)
+
+	if srid {
+		b = e.order().AppendUint32(b, uint32(int32(e.SRID)))
+	}
+
+	return b
+}
+
+// Appends a count of points, then the points.
+func (e WKBEncoder) appendPoints(b []byte, points []Point) []byte {
+	b = e.order().AppendUint32(b, uint32(len(points)))
+	for _, p := range points {
+		b = e.order().AppendUint64(b, wkbFloatBits(p.x))
+		b = e.order().AppendUint64(b, wkbFloatBits(p.y))
+	}
+	return b
+}
+
+// NaN is written as the quiet NaN PostGIS writes for an empty point, rather
+// than Go's.
+func wkbFloatBits(f float64) uint64 {
+	if math.IsNaN(f) {
+		return 0x7ff8000000000000
+	}
+	return math.Float64bits(f)
+}
+
+// Appends a count of rings, then the rings, closed.
+func (e WKBEncoder) appendPolygon(b []byte, pg PolygonWithHoles) ([]byte, error) {
+	if len(pg.Shell.point) == 0 {
+		return e.order().AppendUint32(b, 0), nil
+	}
+
+	b = e.order().AppendUint32(b, uint32(1+len(pg.Holes)))
+	b = e.appendPoints(b, closeRing(pg.Shell.point))
+	for _, h := range pg.Holes {
+		if len(h.point) == 0 {
+			return nil, &GeometryError{Type: "polygon", Reason: "a hole has no points"}
+		}
+		b = e.appendPoints(b, closeRing(h.point))
+	}
+
+	return b, nil
+}
+
+func (e WKBEncoder) append(b []byte, g interface{}, top bool) ([]byte, error) {
+	var err error
+
+	switch g := g.(type) {
+	case Point:
+		b = e.appendHeader(b, wkbPoint, top)
+		b = e.order().AppendUint64(b, wkbFloatBits(g.x))
+		return e.order().AppendUint64(b, wkbFloatBits(g.y)), nil
+	case Segment:
+		return e.appendPoints(e.appendHeader(b, wkbLineString, top), g[:]), nil
+	case Path:
+		points := g.point
+		if g.closed && len(points) > 0 {
+			points = closeRing(points)
+		}
+		return e.appendPoints(e.appendHeader(b, wkbLineString, top), points), nil
+	case Box:
+		return e.appendPolygon(e.appendHeader(b, wkbPolygon, top), PolygonWithHoles{Shell: Polygon{point: boxRing(g)[:4]}})
+	case Polygon:
+		return e.appendPolygon(e.appendHeader(b, wkbPolygon, top), PolygonWithHoles{Shell: g})
+	case PolygonWithHoles:
+		return e.appendPolygon(e.appendHeader(b, wkbPolygon, top), g)
+	case Circle:
+		if e.CircleSegments < 3 {
+			return nil, &GeometryError{Type: "circle", Reason: "WKB has no circles, unless CircleSegments is set to at least 3 to write them as polygons"}
+		}
+		pg, err := g.Polygon(e.CircleSegments)
+		if err != nil {
+			return nil, err
+		}
+		return e.appendPolygon(e.appendHeader(b, wkbPolygon, top), PolygonWithHoles{Shell: pg})
+	case MultiPoint:
+		b = e.order().AppendUint32(e.appendHeader(b, wkbMultiPoint, top), uint32(len(g)))
+		for _, p := range g {
+			if b, err = e.append(b, p, false); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case MultiPath:
+		b = e.order().AppendUint32(e.appendHeader(b, wkbMultiLineString, top), uint32(len(g)))
+		for _, p := range g {
+			if b, err = e.append(b, p, false); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case MultiPolygon:
+		b = e.order().AppendUint32(e.appendHeader(b, wkbMultiPolygon, top), uint32(len(g)))
+		for _, pg := range g {
+			if b, err = e.append(b, pg, false); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case GeometryCollection:
+		b = e.order().AppendUint32(e.appendHeader(b, wkbGeometryCollection, top), uint32(len(g)))
+		for _, m := range g {
+			if b, err = e.append(b, m, false); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("Cannot write %T as WKB", g)
+}
+
+// ----------
+
+// UnmarshalWKB reads a two dimensional geometry from WKB of either flavor
+// and byte order, and returns it with its SRID, which is zero if it has
+// none.  The geometry is of the same type as ParseWKT returns for the WKT
+// of the same type.  Geometries with Z or M coordinates are an error.
+func UnmarshalWKB(data []byte) (interface{}, int, error) {
+	g, err := decodeWKB(data, "wkb")
+	return g.value, g.srid, err
+}
+
+// wkbGeometry is a decoded geometry, with its WKB type and SRID.
+type wkbGeometry struct {
+	value interface{}
+	typ   uint32
+	srid  int
+}
+
+// decodeWKB reads a whole geometry, returning errors for the named type.
+func decodeWKB(data []byte, typeName string) (wkbGeometry, error) {
+	r := &wkbReader{input: data, data: data, typeName: typeName}
+
+	var g wkbGeometry
+	g.value, g.typ = r.geometry(true)
+	g.srid = r.srid
+
+	if r.err == nil && len(r.data) > 0 {
+		r.fail("unexpected data after geometry")
+	}
+
+	if r.err != nil {
+		return wkbGeometry{}, r.err
+	}
+
+	return g, nil
+}
+
+// wkbReader reads values from WKB, keeping the first error so that it need
+// only be checked at the end.  Each geometry has its own byte order.
+type wkbReader struct {
+	input    []byte
+	data     []byte
+	typeName string
+	order    binary.ByteOrder
+	srid     int
+	err      error
+}
+
+func (r *wkbReader) fail(reason string) {
+	if r.err == nil {
+		offset := len(r.input) - len(r.data)
+		r.err = &SyntaxError{Type: r.typeName, Input: hex.EncodeToString(r.input), Reason: fmt.Sprintf("%s at offset %d", reason, offset)}
+	}
+	r.data = nil
+}
+
+func (r *wkbReader) uint32() uint32 {
+	if len(r.data) < 4 {
+		r.fail("unexpected end of data")
+		return 0
+	}
+
+	v := r.order.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *wkbReader) points(n uint32) []Point {
+	if uint64(len(r.data)) < 16*uint64(n) {
+		r.fail("unexpected end of data")
+		return nil
+	}
+
+	points := make([]Point, n)
+	for i := range points {
+		points[i].x = math.Float64frombits(r.order.Uint64(r.data[16*i:]))
+		points[i].y = math.Float64frombits(r.order.Uint64(r.data[16*i+8:]))
+	}
+
+	r.data = r.data[16*n:]
+	return points
+}
+
+// reads the byte order, type and SRID of a geometry, and returns its type
+// without any dimension flags
+func (r *wkbReader) header(top bool) uint32 {
+	if len(r.data) < 1 {
+		r.fail("unexpected end of data")
+		return 0
+	}
+
+	switch r.data[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		r.fail(fmt.Sprintf("invalid byte order %d", r.data[0]))
+		return 0
+	}
+	r.data = r.data[1:]
+
+	t := r.uint32()
+	z, m := t&ewkbZ != 0, t&ewkbM != 0
+
+	if t&ewkbSRID != 0 {
+		srid := int(int32(r.uint32()))
+		if top {
+			r.srid = srid
+		}
+	}
+
+	t &^= ewkbFlags
+	switch t / 1000 {
+	case 0:
+	case 1:
+		z = true
+	case 2:
+		m = true
+	case 3:
+		z, m = true, true
+	default:
+		r.fail(fmt.Sprintf("invalid geometry type %d", t))
+		return 0
+	}
+	t %= 1000
+
+	if z || m {
+		dims := "Z"
+		if !z {
+			dims = "M"
+		} else if m {
+			dims = "ZM"
+		}
+		r.fail(fmt.Sprintf("%s %s has %s coordinates, but only two dimensional geometries are supported", wkbTypeName(t), dims, dims))
+	}
+
+	return t
+}
+
+// reads a geometry of the given type within a multi-geometry
+func (r *wkbReader) member(want uint32) interface{} {
+	g, t := r.geometry(false)
+	if r.err == nil && t != want {
+		r.fail(fmt.Sprintf("expected %s, got %s", wkbTypeName(want), wkbTypeName(t)))
+	}
+	return g
+}
+
+func (r *wkbReader) geometry(top bool) (interface{}, uint32) {
+	t := r.header(top)
+	if r.err != nil {
+		return nil, t
+	}
+
+	switch t {
+	case wkbPoint:
+		points := r.points(1)
+		if r.err != nil {
+			return nil, t
+		}
+		return points[0], t
+	case wkbLineString:
+		return pathFromPoints(r.points(r.uint32())), t
+	case wkbPolygon:
+		var pg PolygonWithHoles
+		n := r.uint32()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			ring := r.points(r.uint32())
+			if r.err != nil {
+				break
+			}
+			if !isClosedRing(ring) {
+				r.fail("polygon rings must end at their first point")
+				break
+			}
+			if i == 0 {
+				pg.Shell = Polygon{point: ring[:len(ring)-1]}
+			} else {
+				pg.Holes = append(pg.Holes, Polygon{point: ring[:len(ring)-1]})
+			}
+		}
+		return simplestPolygon(pg), t
+	case wkbMultiPoint:
+		var mp MultiPoint
+		n := r.uint32()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			p, _ := r.member(wkbPoint).(Point)
+			mp = append(mp, p)
+		}
+		return mp, t
+	case wkbMultiLineString:
+		var mp MultiPath
+		n := r.uint32()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			p, _ := r.member(wkbLineString).(Path)
+			mp = append(mp, p)
+		}
+		return mp, t
+	case wkbMultiPolygon:
+		var mp MultiPolygon
+		n := r.uint32()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			switch pg := r.member(wkbPolygon).(type) {
+			case Polygon:
+				mp = append(mp, PolygonWithHoles{Shell: pg})
+			case PolygonWithHoles:
+				mp = append(mp, pg)
+			}
+		}
+		return mp, t
+	case wkbGeometryCollection:
+		var gc GeometryCollection
+		n := r.uint32()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			g, _ := r.geometry(false)
+			gc = append(gc, g)
+		}
+		return gc, t
+	}
+
+	r.fail(fmt.Sprintf("unsupported geometry type %d", t))
+	return nil, t
+}
+
+func wkbTypeName(t uint32) string {
+	switch t {
+	case wkbPoint:
+		return "POINT"
+	case wkbLineString:
+		return "LINESTRING"
+	case wkbPolygon:
+		return "POLYGON"
+	case wkbMultiPoint:
+		return "MULTIPOINT"
+	case wkbMultiLineString:
+		return "MULTILINESTRING"
+	case wkbMultiPolygon:
+		return "MULTIPOLYGON"
+	case wkbGeometryCollection:
+		return "GEOMETRYCOLLECTION"
+	}
+	return fmt.Sprintf("geometry type %d", t)
+}
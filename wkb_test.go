@@ -0,0 +1,146 @@
+package geometry
+
+import (
+	"encoding/hex"
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWKB(t *testing.T) {
+
+	Convey("Given geometric values", t, func() {
+		square := NewPolygon(Point{0, 0}, Point{0, 4}, Point{4, 4}, Point{4, 0})
+		hole := NewPolygon(Point{1, 1}, Point{1, 2}, Point{2, 2})
+
+		values := []interface{}{
+			NewPoint(1, 2),
+			NewPath(Point{0, 0}, Point{1, 1}, Point{2, 0}),
+			NewClosedPath(Point{0, 0}, Point{1, 1}, Point{2, 0}),
+			square,
+			PolygonWithHoles{Shell: square, Holes: []Polygon{hole}},
+			MultiPoint{{1, 2}, {3, 4}},
+			MultiPath{NewPath(Point{0, 0}, Point{1, 1}), NewPath(Point{2, 2}, Point{3, 3})},
+			MultiPolygon{{Shell: square, Holes: []Polygon{hole}}, {Shell: hole}},
+			GeometryCollection{NewPoint(1, 2), MultiPoint{{3, 4}}, GeometryCollection(nil)},
+		}
+
+		Convey("They should be written and read back in either byte order and flavor", func() {
+			for _, e := range []WKBEncoder{{}, {BigEndian: true}, {Flavor: WKBExtended, SRID: 4326}} {
+				for _, v := range values {
+					b, err := e.Marshal(v)
+					So(err, ShouldBeNil)
+
+					g, srid, err := UnmarshalWKB(b)
+					So(err, ShouldBeNil)
+					So(g, ShouldResemble, v)
+					So(srid, ShouldEqual, e.SRID)
+				}
+			}
+		})
+
+		Convey("Points should be written as the standard bytes", func() {
+			b, err := MarshalWKB(NewPoint(1, 2))
+			So(err, ShouldBeNil)
+			So(hex.EncodeToString(b), ShouldEqual, "0101000000000000000000f03f0000000000000040")
+
+			b, err = WKBEncoder{BigEndian: true}.Marshal(NewPoint(1, 2))
+			So(err, ShouldBeNil)
+			So(hex.EncodeToString(b), ShouldEqual, "00000000013ff00000000000004000000000000000")
+
+			b, err = WKBEncoder{Flavor: WKBExtended, SRID: 4326}.Marshal(NewPoint(1, 2))
+			So(err, ShouldBeNil)
+			So(hex.EncodeToString(b), ShouldEqual, "0101000020e6100000000000000000f03f0000000000000040")
+		})
+
+		Convey("Segments, boxes and circles should be written as the natural types", func() {
+			b, err := MarshalWKB(NewSegment(Point{1, 2}, Point{3, 4}))
+			So(err, ShouldBeNil)
+			g, _, err := UnmarshalWKB(b)
+			So(err, ShouldBeNil)
+			So(g, ShouldResemble, NewPath(Point{1, 2}, Point{3, 4}))
+
+			b, err = MarshalWKB(NewBox(Point{1, 2}, Point{3, 4}))
+			So(err, ShouldBeNil)
+			g, _, err = UnmarshalWKB(b)
+			So(err, ShouldBeNil)
+			So(g, ShouldResemble, NewPolygon(Point{1, 2}, Point{1, 4}, Point{3, 4}, Point{3, 2}))
+
+			_, err = MarshalWKB(NewCircle(Origin, 1))
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			_, err = WKBEncoder{CircleSegments: 2}.Marshal(NewCircle(Origin, 1))
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			b, err = WKBEncoder{CircleSegments: 8}.Marshal(NewCircle(Origin, 1))
+			So(err, ShouldBeNil)
+			g, _, err = UnmarshalWKB(b)
+			So(err, ShouldBeNil)
+			So(g.(Polygon).Len(), ShouldEqual, 8)
+		})
+
+		Convey("Empty points should have NaN coordinates", func() {
+			b, err := MarshalWKB(NewPoint(math.NaN(), math.NaN()))
+			So(err, ShouldBeNil)
+			So(hex.EncodeToString(b), ShouldEqual, "0101000000000000000000f87f000000000000f87f")
+
+			g, _, err := UnmarshalWKB(b)
+			So(err, ShouldBeNil)
+			So(math.IsNaN(g.(Point).x), ShouldBeTrue)
+		})
+
+		Convey("Members of multi-geometries may have their own byte order", func() {
+			// MULTIPOINT((1 2)), with a big endian point in a little endian collection
+			b, _ := hex.DecodeString("01040000000100000000000000013ff00000000000004000000000000000")
+			g, _, err := UnmarshalWKB(b)
+			So(err, ShouldBeNil)
+			So(g, ShouldResemble, MultiPoint{{1, 2}})
+		})
+
+		Convey("Only extended WKB should have an SRID", func() {
+			_, err := WKBEncoder{SRID: 4326}.Marshal(NewPoint(1, 2))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Z and M coordinates should be reported in either flavor", func() {
+			for s, dims := range map[string]string{
+				"01e9030000000000000000f03f00000000000000400000000000000840":                         "POINT Z",
+				"01d1070000000000000000f03f00000000000000400000000000000840":                         "POINT M",
+				"01b90b0000000000000000f03f000000000000004000000000000008400000000000001040":         "POINT ZM",
+				"0101000080000000000000f03f00000000000000400000000000000840":                         "POINT Z",
+				"01010000e0e6100000000000000000f03f000000000000004000000000000008400000000000001040": "POINT ZM",
+				"0104000000010000000101000040000000000000f03f00000000000000400000000000000840":       "POINT M",
+			} {
+				b, _ := hex.DecodeString(s)
+				_, _, err := UnmarshalWKB(b)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, dims+" has ")
+
+				var se *SyntaxError
+				So(errors.As(err, &se), ShouldBeTrue)
+				So(se.Type, ShouldEqual, "wkb")
+			}
+		})
+
+		Convey("Invalid WKB should return a syntax error", func() {
+			for _, s := range []string{
+				"",
+				"02",
+				"0101000000000000000000f03f",
+				"0101000000000000000000f03f000000000000004000",
+				"0108000000",
+				"01ee0f0000",
+				"010300000001000000030000000000000000000000000000000000000000000000000000000000000000000000f03f000000000000f03f0000000000000000",
+				"0104000000010000000102000000",
+				"010200000000000080",
+			} {
+				b, _ := hex.DecodeString(s)
+				_, _, err := UnmarshalWKB(b)
+				So(err, ShouldNotBeNil)
+				So(strings.HasPrefix(err.Error(), "Invalid input syntax for type wkb"), ShouldBeTrue)
+			}
+		})
+	})
+}
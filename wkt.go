@@ -0,0 +1,475 @@
+package geometry
+
+// Well-Known Text, the text format of the OGC simple features spec, which
+// GIS tools use to exchange shapes.  Only two dimensional geometries are
+// supported.
+// info from https://www.ogc.org/standard/sfa/ and PostGIS' ST_AsText
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WKTEncoder writes geometries as WKT.  WKT has no circles, so circles are
+// written as polygons of CircleSegments points around the circle, or are an
+// error if CircleSegments is less than 3.
+type WKTEncoder struct {
+	CircleSegments int
+	Precision      Precision // of the coordinates, or Lossless if unset
+}
+
+// MarshalWKT returns the WKT of a geometry, which may be a Point, Segment,
+// Box, Path, Polygon, PolygonWithHoles, MultiPoint, MultiPath, MultiPolygon
+// or GeometryCollection.  A Segment is written as a LINESTRING of two
+// points, a Box as a rectangular POLYGON, and a closed Path as a LINESTRING
+// which ends at its first point.
+func MarshalWKT(g interface{}) ([]byte, error) {
+	return WKTEncoder{}.Marshal(g)
+}
+
+// Marshal returns the WKT of a geometry, as MarshalWKT does, and also of
+// a Circle if CircleSegments is set.
+func (e WKTEncoder) Marshal(g interface{}) ([]byte, error) {
+	return e.append(make([]byte, 0, 32), g)
+}
+
+func (e WKTEncoder) append(b []byte, g interface{}) ([]byte, error) {
+	var err error
+
+	switch g := g.(type) {
+	case Point:
+		b = append(b, "POINT"...)
+		if math.IsNaN(g.x) && math.IsNaN(g.y) {
+			return append(b, " EMPTY"...), nil
+		}
+		return e.appendPoints(b, []Point{g})
+	case Segment:
+		return e.appendPoints(append(b, "LINESTRING"...), g[:])
+	case Path:
+		return e.appendPath(append(b, "LINESTRING"...), g)
+	case Box:
+		return e.appendRings(append(b, "POLYGON"...), [][]Point{boxRing(g)})
+	case Polygon:
+		return e.appendPolygon(append(b, "POLYGON"...), PolygonWithHoles{Shell: g})
+	case PolygonWithHoles:
+		return e.appendPolygon(append(b, "POLYGON"...), g)
+	case Circle:
+		if e.CircleSegments < 3 {
+			return nil, &GeometryError{Type: "circle", Reason: "WKT has no circles, unless CircleSegments is set to at least 3 to write them as polygons"}
+		}
+		pg, err := g.Polygon(e.CircleSegments)
+		if err != nil {
+			return nil, err
+		}
+		return e.appendPolygon(append(b, "POLYGON"...), PolygonWithHoles{Shell: pg})
+	case MultiPoint:
+		b = append(b, "MULTIPOINT"...)
+		if len(g) == 0 {
+			return append(b, " EMPTY"...), nil
+		}
+		b = append(b, '(')
+		for i, p := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.appendPoints(b, []Point{p}); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ')'), nil
+	case MultiPath:
+		b = append(b, "MULTILINESTRING"...)
+		if len(g) == 0 {
+			return append(b, " EMPTY"...), nil
+		}
+		b = append(b, '(')
+		for i, p := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.appendPath(b, p); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ')'), nil
+	case MultiPolygon:
+		b = append(b, "MULTIPOLYGON"...)
+		if len(g) == 0 {
+			return append(b, " EMPTY"...), nil
+		}
+		b = append(b, '(')
+		for i, pg := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.appendPolygon(b, pg); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ')'), nil
+	case GeometryCollection:
+		b = append(b, "GEOMETRYCOLLECTION"...)
+		if len(g) == 0 {
+			return append(b, " EMPTY"...), nil
+		}
+		b = append(b, '(')
+		for i, m := range g {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.append(b, m); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ')'), nil
+	}
+
+	return nil, fmt.Errorf("Cannot write %T as WKT", g)
+}
+
+// Appends a list of coordinates in parentheses, like (1 2,3 4), or EMPTY.
+func (e WKTEncoder) appendPoints(b []byte, points []Point) ([]byte, error) {
+	if len(points) == 0 {
+		return append(b, " EMPTY"...), nil
+	}
+
+	b = append(b, '(')
+	for i, p := range points {
+		if math.IsNaN(p.x) || math.IsNaN(p.y) || math.IsInf(p.x, 0) || math.IsInf(p.y, 0) {
+			return nil, &GeometryError{Type: "point", Reason: "WKT coordinates must be finite"}
+		}
+
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = e.Precision.appendFloat(b, p.x)
+		b = append(b, ' ')
+		b = e.Precision.appendFloat(b, p.y)
+	}
+
+	return append(b, ')'), nil
+}
+
+// Appends a list of rings in parentheses, like ((0 0,0 1,1 0,0 0)), or EMPTY.
+func (e WKTEncoder) appendRings(b []byte, rings [][]Point) ([]byte, error) {
+	if len(rings) == 0 {
+		return append(b, " EMPTY"...), nil
+	}
+
+	var err error
+
+	b = append(b, '(')
+	for i, ring := range rings {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if b, err = e.appendPoints(b, ring); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(b, ')'), nil
+}
+
+func (e WKTEncoder) appendPath(b []byte, p Path) ([]byte, error) {
+	points := p.point
+	if p.closed && len(points) > 0 {
+		points = closeRing(points)
+	}
+	return e.appendPoints(b, points)
+}
+
+func (e WKTEncoder) appendPolygon(b []byte, pg PolygonWithHoles) ([]byte, error) {
+	if len(pg.Shell.point) == 0 {
+		return e.appendRings(b, nil)
+	}
+
+	rings := make([][]Point, 0, 1+len(pg.Holes))
+	rings = append(rings, closeRing(pg.Shell.point))
+	for _, h := range pg.Holes {
+		if len(h.point) == 0 {
+			return nil, &GeometryError{Type: "polygon", Reason: "a hole has no points"}
+		}
+		rings = append(rings, closeRing(h.point))
+	}
+
+	return e.appendRings(b, rings)
+}
+
+// ----------
+
+// ParseWKT reads a two dimensional geometry from WKT.  Keywords may be in
+// any case.  The result is a:
+//
+//	POINT               Point, with NaN coordinates if EMPTY
+//	LINESTRING          Path, which is closed if it ends at its first point
+//	POLYGON             Polygon, or PolygonWithHoles if it has holes
+//	MULTIPOINT          MultiPoint
+//	MULTILINESTRING     MultiPath
+//	MULTIPOLYGON        MultiPolygon
+//	GEOMETRYCOLLECTION  GeometryCollection
+func ParseWKT(s string) (interface{}, error) {
+	p := &wktParser{input: s}
+
+	g := p.geometry()
+	if p.err == nil && !p.atEnd() {
+		p.fail("unexpected text after geometry")
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return g, nil
+}
+
+// wktParser reads WKT, keeping the first error so that it need only be
+// checked at the end.
+type wktParser struct {
+	input string
+	pos   int
+	err   error
+}
+
+func (p *wktParser) fail(reason string) {
+	if p.err == nil {
+		p.err = &SyntaxError{Type: "wkt", Input: p.input, Reason: fmt.Sprintf("%s at offset %d", reason, p.pos)}
+	}
+	// stop reading
+	p.pos = len(p.input)
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *wktParser) atEnd() bool {
+	p.skipSpace()
+	return p.pos == len(p.input)
+}
+
+// returns the next character, or 0 at the end
+func (p *wktParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// consumes c if it is the next character
+func (p *wktParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(c byte) {
+	if !p.accept(c) {
+		p.fail(fmt.Sprintf("expected '%c'", c))
+	}
+}
+
+// reads a keyword, in upper case, or returns "" if the next text is not one
+func (p *wktParser) keyword() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos] | 0x20
+		if c < 'a' || c > 'z' {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.input[start:p.pos])
+}
+
+// consumes EMPTY if it is next, otherwise the opening parenthesis of a list
+func (p *wktParser) empty() bool {
+	start := p.pos
+	if p.keyword() == "EMPTY" {
+		return true
+	}
+	p.pos = start
+	p.expect('(')
+	return false
+}
+
+func (p *wktParser) number() float64 {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("0123456789+-.eE", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		p.fail("expected a number")
+	}
+	return f
+}
+
+func (p *wktParser) point() Point {
+	pt := Point{x: p.number(), y: p.number()}
+
+	// a third number would be a Z or M coordinate
+	if c := p.peek(); p.err == nil && c != ',' && c != ')' {
+		p.fail("only two dimensional geometries are supported")
+	}
+
+	return pt
+}
+
+// reads a list of coordinates, like (1 2,3 4), or EMPTY
+func (p *wktParser) points() []Point {
+	if p.empty() {
+		return nil
+	}
+
+	var points []Point
+	for p.err == nil {
+		points = append(points, p.point())
+		if !p.accept(',') {
+			break
+		}
+	}
+	p.expect(')')
+
+	return points
+}
+
+// reads a list of coordinates, as a path which is closed if it ends at its
+// first point
+func (p *wktParser) path() Path {
+	return pathFromPoints(p.points())
+}
+
+// reads a list of rings, like ((0 0,0 1,1 0,0 0)), or EMPTY
+func (p *wktParser) polygon() PolygonWithHoles {
+	var pg PolygonWithHoles
+
+	if p.empty() {
+		return pg
+	}
+
+	for i := 0; p.err == nil; i++ {
+		ring := p.points()
+		if p.err != nil {
+			break
+		}
+		if !isClosedRing(ring) {
+			p.fail("polygon rings must end at their first point")
+			break
+		}
+
+		if i == 0 {
+			pg.Shell = Polygon{point: ring[:len(ring)-1]}
+		} else {
+			pg.Holes = append(pg.Holes, Polygon{point: ring[:len(ring)-1]})
+		}
+
+		if !p.accept(',') {
+			break
+		}
+	}
+	p.expect(')')
+
+	return pg
+}
+
+func (p *wktParser) geometry() interface{} {
+	typ := p.keyword()
+
+	start := p.pos
+	switch p.keyword() {
+	case "Z", "M", "ZM":
+		p.fail("only two dimensional geometries are supported")
+	}
+	p.pos = start
+
+	switch typ {
+	case "POINT":
+		if p.empty() {
+			return Point{x: math.NaN(), y: math.NaN()}
+		}
+		pt := p.point()
+		p.expect(')')
+		return pt
+	case "LINESTRING":
+		return p.path()
+	case "POLYGON":
+		return simplestPolygon(p.polygon())
+	case "MULTIPOINT":
+		var mp MultiPoint
+		if p.empty() {
+			return mp
+		}
+		for p.err == nil {
+			// the points may be in parentheses or not
+			if p.accept('(') {
+				mp = append(mp, p.point())
+				p.expect(')')
+			} else {
+				mp = append(mp, p.point())
+			}
+			if !p.accept(',') {
+				break
+			}
+		}
+		p.expect(')')
+		return mp
+	case "MULTILINESTRING":
+		var mp MultiPath
+		if p.empty() {
+			return mp
+		}
+		for p.err == nil {
+			mp = append(mp, p.path())
+			if !p.accept(',') {
+				break
+			}
+		}
+		p.expect(')')
+		return mp
+	case "MULTIPOLYGON":
+		var mp MultiPolygon
+		if p.empty() {
+			return mp
+		}
+		for p.err == nil {
+			mp = append(mp, p.polygon())
+			if !p.accept(',') {
+				break
+			}
+		}
+		p.expect(')')
+		return mp
+	case "GEOMETRYCOLLECTION":
+		var gc GeometryCollection
+		if p.empty() {
+			return gc
+		}
+		for p.err == nil {
+			gc = append(gc, p.geometry())
+			if !p.accept(',') {
+				break
+			}
+		}
+		p.expect(')')
+		return gc
+	case "":
+		p.fail("expected a geometry type")
+	default:
+		p.fail(fmt.Sprintf("unsupported geometry type %s", typ))
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package geometry
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestWKT(t *testing.T) {
+
+	Convey("Given geometric values and their WKT", t, func() {
+		square := NewPolygon(Point{0, 0}, Point{0, 4}, Point{4, 4}, Point{4, 0})
+		hole := NewPolygon(Point{1, 1}, Point{1, 2}, Point{2, 2})
+
+		tests := []struct {
+			value interface{}
+			wkt   string
+		}{
+			{NewPoint(1, 2), "POINT(1 2)"},
+			{NewPoint(-0.5, 1e-300), "POINT(-0.5 1e-300)"},
+			{NewPath(Point{0, 0}, Point{1, 1}, Point{2, 0}), "LINESTRING(0 0,1 1,2 0)"},
+			{NewClosedPath(Point{0, 0}, Point{1, 1}, Point{2, 0}), "LINESTRING(0 0,1 1,2 0,0 0)"},
+			{Path{}, "LINESTRING EMPTY"},
+			{square, "POLYGON((0 0,0 4,4 4,4 0,0 0))"},
+			{Polygon{}, "POLYGON EMPTY"},
+			{PolygonWithHoles{Shell: square, Holes: []Polygon{hole}}, "POLYGON((0 0,0 4,4 4,4 0,0 0),(1 1,1 2,2 2,1 1))"},
+			{MultiPoint{{1, 2}, {3, 4}}, "MULTIPOINT((1 2),(3 4))"},
+			{MultiPoint(nil), "MULTIPOINT EMPTY"},
+			{MultiPath{NewPath(Point{0, 0}, Point{1, 1}), NewPath(Point{2, 2}, Point{3, 3})}, "MULTILINESTRING((0 0,1 1),(2 2,3 3))"},
+			{MultiPolygon{{Shell: square, Holes: []Polygon{hole}}, {Shell: hole}}, "MULTIPOLYGON(((0 0,0 4,4 4,4 0,0 0),(1 1,1 2,2 2,1 1)),((1 1,1 2,2 2,1 1)))"},
+			{GeometryCollection{NewPoint(1, 2), NewPath(Point{0, 0}, Point{1, 1}), GeometryCollection(nil)}, "GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(0 0,1 1),GEOMETRYCOLLECTION EMPTY)"},
+		}
+
+		Convey("They should be written and parsed back", func() {
+			for _, test := range tests {
+				b, err := MarshalWKT(test.value)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, test.wkt)
+
+				g, err := ParseWKT(test.wkt)
+				So(err, ShouldBeNil)
+				So(g, ShouldResemble, test.value)
+			}
+		})
+
+		Convey("Segments and boxes should be written as the natural WKT types", func() {
+			b, err := MarshalWKT(NewSegment(Point{1, 2}, Point{3, 4}))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "LINESTRING(1 2,3 4)")
+
+			b, err = MarshalWKT(NewBox(Point{3, 4}, Point{1, 2}))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "POLYGON((1 2,1 4,3 4,3 2,1 2))")
+		})
+
+		Convey("Empty points should have NaN coordinates", func() {
+			g, err := ParseWKT("POINT EMPTY")
+			So(err, ShouldBeNil)
+			So(math.IsNaN(g.(Point).x), ShouldBeTrue)
+
+			b, err := MarshalWKT(g)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "POINT EMPTY")
+		})
+
+		Convey("Circles should only be written as polygons when asked", func() {
+			c := NewCircle(Point{0, 0}, 1)
+
+			_, err := MarshalWKT(c)
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			for _, n := range []int{2, 1, -1} {
+				_, err := WKTEncoder{CircleSegments: n}.Marshal(c)
+				So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+			}
+
+			b, err := WKTEncoder{CircleSegments: 4}.Marshal(c)
+			So(err, ShouldBeNil)
+			g, err := ParseWKT(string(b))
+			So(err, ShouldBeNil)
+			So(g.(Polygon).Len(), ShouldEqual, 4)
+			So(g.(Polygon).Point(0), ShouldResemble, NewPoint(-1, 0))
+		})
+
+		Convey("Other spellings should be accepted", func() {
+			for s, want := range map[string]interface{}{
+				" point ( 1  2 ) ":                 NewPoint(1, 2),
+				"MultiPoint (1 2, 3 4)":            MultiPoint{{1, 2}, {3, 4}},
+				"MULTIPOINT((1 2),3 4)":            MultiPoint{{1, 2}, {3, 4}},
+				"LINESTRING(1 2,3 4,1 2)":          NewClosedPath(Point{1, 2}, Point{3, 4}),
+				"LINESTRING(1 2,1 2)":              NewPath(Point{1, 2}, Point{1, 2}),
+				"POLYGON((0 0,0 1,1 0,0 0))":       NewPolygon(Point{0, 0}, Point{0, 1}, Point{1, 0}),
+				"GEOMETRYCOLLECTION(POINT(+1 -2))": GeometryCollection{NewPoint(1, -2)},
+			} {
+				g, err := ParseWKT(s)
+				So(err, ShouldBeNil)
+				So(g, ShouldResemble, want)
+			}
+		})
+
+		Convey("Invalid WKT should return a syntax error", func() {
+			for _, s := range []string{
+				"",
+				"POINT",
+				"POINT(1)",
+				"POINT(1 2",
+				"POINT(1 2) x",
+				"POINT Z (1 2 3)",
+				"POINT(1 2 3)",
+				"CIRCULARSTRING(0 0,1 1,2 0)",
+				"POLYGON((0 0,0 1,1 0))",
+				"POLYGON(())",
+				"MULTIPOLYGON(((0 0,0 1,1 0,0 0)),)",
+				"GEOMETRYCOLLECTION(POINT(1 2),FOO)",
+			} {
+				_, err := ParseWKT(s)
+				So(err, ShouldNotBeNil)
+				var se *SyntaxError
+				So(errors.As(err, &se), ShouldBeTrue)
+				So(se.Type, ShouldEqual, "wkt")
+			}
+		})
+
+		Convey("Values WKT cannot represent should return an error", func() {
+			_, err := MarshalWKT(NewLine(Point{0, 0}, Point{1, 1}))
+			So(err, ShouldNotBeNil)
+
+			_, err = MarshalWKT(NewPoint(math.Inf(1), 0))
+			So(errors.Is(err, ErrInvalidGeometry), ShouldBeTrue)
+
+			_, err = MarshalWKT(MultiPoint{{x: math.NaN(), y: math.NaN()}})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}